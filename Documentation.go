@@ -4,7 +4,6 @@
 // Fix: combinable enums is not RAML 1.0 compliant
 // Features: Annotations
 // Features: Traits
-// Improvement: Handle array type definitions like: (string | Person)[] (https://github.com/raml-org/raml-spec/blob/master/versions/raml-10/raml-10.md/#type-expressions)
 // Improvement: Create files for types to include
 // Tests/examples: SecuritySchemes
 
@@ -18,6 +17,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/florenthobein/godoc2api/raml"
@@ -41,12 +41,29 @@ type Documentation struct {
 	Description       string
 	Version           string
 	URL               string
+	BaseURIParameters map[string]Parameter // parameters of the URL, ex: `{region}`
+	Protocols         []string             // HTTP and/or HTTPS
 	MediaType         string
 	UserDocumentation []map[string]string
+	SecuredBy         []string // tags of the securities applied by default to all the routes, ex: `[]string{"auth"}`
+	Traits            []string // tags of the traits applied by default to all the routes
 	routes            map[string]Route
 	types             map[string]Type
-	traits            map[string]Trait
 	annotations       map[string]Annotation
+	prefixes          map[string]prefixDefaults
+	resources         map[string]resourceDescription
+}
+
+// Display name and description of a resource
+type resourceDescription struct {
+	displayName string
+	description string
+}
+
+// Securities and traits applied by default to the routes under a path prefix
+type prefixDefaults struct {
+	securities []string
+	traits     []string
 }
 
 // Add a route to the documentation.
@@ -89,15 +106,266 @@ type Documentation struct {
 //	d := godoc2api.Documentation{}
 //	d.AddRoute(MyRouteDefinition{ "GET /myroute", "MyObject" })
 func (d *Documentation) AddRoute(user_route interface{}) error {
+	return d.addRoute(user_route, "", "")
+}
+
+// Create the RAML parameters of the URL, ex: `region` for `https://{region}.example.com`.
+// The parameter `version` is described by the version of the API if it's not defined.
+func (d *Documentation) baseURIParametersToRAML() (map[string]raml.Type, error) {
+	names := map[string]bool{}
+	for _, res := range regexp.MustCompile(`\{([^\}]+)\}`).FindAllStringSubmatch(d.URL, -1) {
+		names[res[1]] = true
+	}
+	for name := range d.BaseURIParameters {
+		if !names[name] {
+			warn("base URI parameter `%s` not found in the URL %s", name, d.URL)
+		}
+	}
+
+	parameters := map[string]raml.Type{}
+	for name := range names {
+		p, ok := d.BaseURIParameters[name]
+		if !ok {
+			if name == "version" && d.Version != "" {
+				parameters[name] = raml.Type{
+					Type:        "string",
+					Description: "Version of the API",
+					Enum:        []raml.AnyType{d.Version},
+				}
+			}
+			continue
+		}
+		p.Name = name
+		if p.Type == "" {
+			p.Type = "string"
+		}
+		_, precise, register_types, err := formatType(string(p.Type))
+		if err != nil {
+			return nil, fmt.Errorf("wrong type for the base URI parameter %s: %v", name, err)
+		}
+		p.Type = precise
+		for _, t := range register_types {
+			d.addType(t)
+		}
+		t, err := p.toRAML()
+		if err != nil {
+			return nil, err
+		}
+		parameters[name] = t
+	}
+	if len(parameters) == 0 {
+		return nil, nil
+	}
+	return parameters, nil
+}
+
+// Protocols of the API, the one of the URL unless they're defined.
+// The protocol of the URL is ignored with a warning if it's not HTTP or HTTPS.
+func (d *Documentation) protocols() ([]string, error) {
+	if len(d.Protocols) != 0 {
+		return parseProtocols(d.Protocols)
+	}
+	if i := strings.Index(d.URL, "://"); i >= 0 {
+		protocols, err := parseProtocols([]string{d.URL[:i]})
+		if err != nil {
+			warn("%v in the URL %s", err, d.URL)
+			return nil, nil
+		}
+		return protocols, nil
+	}
+	return nil, nil
+}
+
+// Describe a resource, ex: `/books`, with a display name and a description.
+// The resource doesn't need a route of its own, as long as it has nested resources,
+// in which case it's only rendered as their parent.
+// The resources can also be described by the tag `@resourceDescription` of their routes.
+//
+// Example
+//	d.DescribeResource("/books", "Books", "The catalogue of the library")
+func (d *Documentation) DescribeResource(resource, display_name, description string) {
+	if d.resources == nil {
+		d.resources = map[string]resourceDescription{}
+	}
+	resource = "/" + strings.Trim(resource, "/")
+	d.resources[resource] = resourceDescription{displayName: display_name, description: description}
+}
+
+// Describe the RAML resources, creating the parents that have no route
+func (d *Documentation) describeResources(index *map[string]raml.Resource) {
+	uris := []string{}
+	for uri := range d.resources {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	for _, uri := range uris {
+		rd := d.resources[uri]
+		res, ok := (*index)[uri]
+		if !ok {
+			// Find the first nested resource, for the parameters of the URI
+			child_uris := []string{}
+			for child_uri := range *index {
+				if strings.HasPrefix(child_uri, uri+"/") {
+					child_uris = append(child_uris, child_uri)
+				}
+			}
+			sort.Strings(child_uris)
+			if len(child_uris) != 0 {
+				res, ok = raml.Resource{URI: uri}, true
+				for name, p := range (*index)[child_uris[0]].URIParameters {
+					if strings.Contains(uri, "{"+name+"}") {
+						if res.URIParameters == nil {
+							res.URIParameters = map[string]raml.Type{}
+						}
+						res.URIParameters[name] = p
+					}
+				}
+			}
+		}
+		if !ok {
+			warn("no route for the resource %s described", uri)
+			continue
+		}
+		if rd.displayName != "" {
+			res.DisplayName = rd.displayName
+		}
+		if rd.description != "" {
+			res.Description = rd.description
+		}
+		(*index)[uri] = res
+	}
+}
+
+// Secure by default the routes under a path prefix, ex: `/admin` for `/admin/users`.
+// The securities of the longest prefix prevail over the ones of the shorter prefixes
+// and over `SecuredBy`, and a route can still define its own securities
+// or be declared public with the tag `@public`.
+//
+// Example
+//	d.SecurePrefix("/admin", "auth")
+func (d *Documentation) SecurePrefix(prefix string, security_tags ...string) {
+	d.setPrefixDefaults(prefix, func(p *prefixDefaults) {
+		p.securities = append(p.securities, security_tags...)
+	})
+}
+
+// Apply by default traits to the routes under a path prefix, ex: `/books` for `/books/{id}`.
+// The traits add up to the ones of the shorter prefixes and of `Traits`.
+//
+// Example
+//	d.ApplyTraits("/books", "pagination")
+func (d *Documentation) ApplyTraits(prefix string, trait_tags ...string) {
+	d.setPrefixDefaults(prefix, func(p *prefixDefaults) {
+		p.traits = append(p.traits, trait_tags...)
+	})
+}
+
+func (d *Documentation) setPrefixDefaults(prefix string, set func(*prefixDefaults)) {
+	if d.prefixes == nil {
+		d.prefixes = map[string]prefixDefaults{}
+	}
+	prefix = "/" + strings.Trim(prefix, "/")
+	p := d.prefixes[prefix]
+	set(&p)
+	d.prefixes[prefix] = p
+}
+
+// Prefixes matching a resource, from the shortest to the longest
+func (d *Documentation) matchingPrefixes(resource string) []string {
+	matching := []string{}
+	for prefix := range d.prefixes {
+		if prefix == "/" || resource == prefix || strings.HasPrefix(resource, prefix+"/") {
+			matching = append(matching, prefix)
+		}
+	}
+	sort.Strings(matching)
+	return matching
+}
+
+// Tags of the securities applied by default to all the routes
+func (d *Documentation) defaultSecurities() []string {
+	return definedTags(d.SecuredBy, isReservedSecurity, "security")
+}
+
+// Tags of the securities securing the routes, by default or not
+func (d *Documentation) usedSecurities() map[string]bool {
+	used := map[string]bool{}
+	for _, tag := range d.defaultSecurities() {
+		used[tag] = true
+	}
+	for _, r := range d.routes {
+		for _, tag := range r.securities() {
+			used[tag] = true
+		}
+	}
+	return used
+}
+
+// Tags of the traits used by the routes, by default or not
+func (d *Documentation) usedTraits() map[string]bool {
+	used := map[string]bool{}
+	for _, r := range d.routes {
+		for tag := range r.Traits {
+			used[tag] = true
+		}
+		for _, tag := range d.prefixTraits(r.Resource) {
+			used[tag] = true
+		}
+	}
+	return used
+}
+
+// Tags of the securities applied by default to a resource by its longest prefix
+func (d *Documentation) prefixSecurities(resource string) []string {
+	prefixes := d.matchingPrefixes(resource)
+	for i := len(prefixes) - 1; i >= 0; i-- {
+		if securities := d.prefixes[prefixes[i]].securities; len(securities) != 0 {
+			return definedTags(securities, isReservedSecurity, "security")
+		}
+	}
+	return nil
+}
+
+// Tags of the traits applied by default to a resource
+func (d *Documentation) prefixTraits(resource string) []string {
+	traits := append([]string{}, d.Traits...)
+	for _, prefix := range d.matchingPrefixes(resource) {
+		traits = append(traits, d.prefixes[prefix].traits...)
+	}
+	return definedTags(traits, isReservedTrait, "trait")
+}
+
+// Sorted tags without duplicates, the undefined ones ignored with a warning
+func definedTags(tags []string, is_defined func(string) bool, kind string) []string {
+	found := map[string]bool{}
+	res := []string{}
+	for _, tag := range tags {
+		if found[tag] {
+			continue
+		}
+		found[tag] = true
+		if !is_defined(tag) {
+			warn("%s `%s` not defined", kind, tag)
+			continue
+		}
+		res = append(res, tag)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Add a route, eventually forcing its method and resource
+func (d *Documentation) addRoute(user_route interface{}, method, resource string) error {
 
 	r := Route{_documentation: d}
 
 	// Read the comment
-	c, extra, err := readComment(user_route)
+	c, extra, pkg_path, err := readComment(user_route)
 	if err != nil {
 		warn("%v (%v)", err, user_route)
 		return err
 	}
+	r._package = pkg_path
 
 	// If `user_route` is a struct and tags are already defined inside,
 	// fill the route with it
@@ -121,6 +389,18 @@ func (d *Documentation) AddRoute(user_route interface{}) error {
 		}
 	}
 
+	// The method and the resource given by a router prevail
+	if method != "" {
+		if err := r.addTag(TAG_METHOD, method); err != nil {
+			warn("%v (%v)", err, user_route)
+		}
+	}
+	if resource != "" {
+		if err := r.addTag(TAG_RESOURCE, resource); err != nil {
+			warn("%v (%v)", err, user_route)
+		}
+	}
+
 	// Check if the route can be used
 	if err := r.checkViability(); err != nil {
 		warn("unusable route: %v (%v)", err, user_route)
@@ -247,11 +527,17 @@ func (d *Documentation) addType(t Type) bool {
 	if _, ok := d.types[string(t)]; ok {
 		return false
 	}
-	d.types[string(t)] = t
 
-	other_ts := extractTypes(string(t))
-	for _, t := range other_ts {
-		d.addType(t)
+	// Walk the graph of the types without recursion,
+	// the types already stored break the cycles
+	queue := []Type{t}
+	for len(queue) > 0 {
+		t, queue = queue[0], queue[1:]
+		if _, ok := d.types[string(t)]; ok {
+			continue
+		}
+		d.types[string(t)] = t
+		queue = append(queue, extractTypes(string(t))...)
 	}
 
 	return true
@@ -268,6 +554,17 @@ func (d *Documentation) toRAML() (raml.Root, error) {
 		Documentation: d.UserDocumentation,
 	}
 
+	// Describe the base URI
+	var err error
+	api.BaseURIParameters, err = d.baseURIParametersToRAML()
+	if err != nil {
+		return api, err
+	}
+	api.Protocols, err = d.protocols()
+	if err != nil {
+		return api, err
+	}
+
 	// Create the resources
 	if d.routes != nil {
 		api.Resources = make(map[string]raml.Resource)
@@ -277,10 +574,42 @@ func (d *Documentation) toRAML() (raml.Root, error) {
 				return api, fmt.Errorf("error while RAMLing resource %s: %v", r.Resource, err)
 			}
 		}
+		d.describeResources(&api.Resources)
 		// Pile the resources
 		api.PileResources()
 	}
 
+	// Create the resource types used by the routes
+	used, names := map[string]bool{}, []string{}
+	for _, r := range d.routes {
+		if r.ResourceType != "" && !used[r.ResourceType] {
+			used[r.ResourceType] = true
+			names = append(names, r.ResourceType)
+		}
+	}
+	if len(names) != 0 {
+		api.ResourceTypes = make(map[string]raml.ResourceType)
+		register_types, err := resourceTypesToRAML(&api.ResourceTypes, names)
+		if err != nil {
+			return api, err
+		}
+		for _, t := range register_types {
+			d.addType(t)
+		}
+	}
+
+	// Register the types used by the security schemes
+	used_securities := d.usedSecurities()
+	if hasReservedSecurity() {
+		register_types, err := securitiesTypes(used_securities)
+		if err != nil {
+			return api, err
+		}
+		for _, t := range register_types {
+			d.addType(t)
+		}
+	}
+
 	// Create the types
 	if d.types != nil {
 		api.Types = make(map[string]raml.Type)
@@ -303,21 +632,22 @@ func (d *Documentation) toRAML() (raml.Root, error) {
 		}
 	}
 
-	// Create the traits
-	if d.traits != nil {
+	// Create the traits used by the routes
+	if used_traits := d.usedTraits(); hasReservedTrait() && len(used_traits) != 0 {
 		api.Traits = make(map[string]raml.Trait)
-		for _, t := range d.traits {
-			err := t.fillToRAML(&api.Traits)
-			if err != nil {
-				return api, fmt.Errorf("error while RAMLing trait %s: %v", t, err)
-			}
-		}
+		traitsToRAML(&api.Traits, used_traits)
 	}
 
-	// Create the security schemes globaly defined
-	if hasReservedSecurity() {
+	// Create the security schemes used by the routes
+	if hasReservedSecurity() && len(used_securities) != 0 {
 		api.SecuritySchemes = make(map[string]raml.SecurityScheme)
-		securitiesToRAML(&api.SecuritySchemes)
+		err := securitiesToRAML(&api.SecuritySchemes, used_securities)
+		if err != nil {
+			return api, err
+		}
+		for _, security := range d.defaultSecurities() {
+			api.SecuredBy = append(api.SecuredBy, security)
+		}
 	}
 
 	return api, nil
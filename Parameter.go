@@ -9,6 +9,8 @@ type Parameter struct {
 	Enum        []interface{}
 	Example     string
 	Default     interface{}
+
+	goType string // go type of the parameter, ex: int64
 }
 
 func (p *Parameter) toRAML() (t raml.Type, err error) {
@@ -17,6 +19,7 @@ func (p *Parameter) toRAML() (t raml.Type, err error) {
 		Type:        p.Type,
 		Description: p.Description,
 	}
+	applyScalarFacets(p.goType, &t)
 	enum := []raml.AnyType{}
 	if p.Enum != nil {
 		for _, e := range p.Enum {
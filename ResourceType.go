@@ -0,0 +1,233 @@
+package godoc2api
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/florenthobein/godoc2api/raml"
+)
+
+// Resource type, mirror of the RAML equivalent.
+// The descriptions and the types can use the parameters given by the resources
+// applying the resource type, ex: `<<item>>`, and the reserved parameters
+// `<<resourcePath>>` and `<<resourcePathName>>`.
+type ResourceType struct {
+	Description string
+	Usage       string
+	Methods     map[string]ResourceTypeMethod // by HTTP method, ex: GET
+}
+
+// Method of a resource type
+type ResourceTypeMethod struct {
+	Description     string
+	QueryParameters map[string]Parameter
+	Body            Type // ex: `<<item>>`
+	Response        Type // ex: `<<item>>[]`
+}
+
+// Registry of resource types
+var index_resource_types map[string]ResourceType
+
+// Regex to match a parameter of a resource type, ex: `item={Book}` or `label=books`
+const _PARSE_RESOURCE_TYPE_PARAMETER = `^(\w+)=(?:\{(.+)\}|(.+))$`
+
+// Configure a new resource type.
+// A route applies it to its resource with the tag `@resourceType`,
+// followed by the name of the resource type and its parameters,
+// the types being written between braces.
+//
+// Example
+//	DefineResourceType("collection", ResourceType{
+//		Description: "Collection of <<resourcePathName>>",
+//		Methods: map[string]ResourceTypeMethod{
+//			"GET":  {Description: "List the <<resourcePathName>>", Response: "<<item>>[]"},
+//			"POST": {Description: "Add an item", Body: "<<item>>"},
+//		},
+//	})
+//
+//	// List the books
+//	// @resource GET /books
+//	// @resourceType collection item={Book}
+//	func ListBooksHandler(http.ResponseWriter, *http.Request) { ... }
+func DefineResourceType(name string, rt ResourceType) {
+	if index_resource_types == nil {
+		index_resource_types = map[string]ResourceType{}
+	}
+	methods := map[string]ResourceTypeMethod{}
+	for method, m := range rt.Methods {
+		method, err := parseMethod(method)
+		if err != nil {
+			warn("%v for the resource type %s", err, name)
+			continue
+		}
+		methods[method] = m
+	}
+	rt.Methods = methods
+	index_resource_types[name] = rt
+}
+
+// Parse the use of a resource type, ex: `collection item={Book | nil} label=books`
+func parseResourceType(line string) (name string, parameters map[string]string, err error) {
+	fields, err := splitResourceType(line)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(fields) == 0 {
+		return "", nil, fmt.Errorf("missing name for the resource type")
+	}
+	name = fields[0]
+	if _, ok := index_resource_types[name]; !ok {
+		return "", nil, fmt.Errorf("resource type `%s` not defined", name)
+	}
+	re := regexp.MustCompile(_PARSE_RESOURCE_TYPE_PARAMETER)
+	for _, field := range fields[1:] {
+		res := re.FindStringSubmatch(field)
+		if len(res) == 0 {
+			return "", nil, fmt.Errorf("wrong parameter `%s` for the resource type %s", field, name)
+		}
+		if parameters == nil {
+			parameters = map[string]string{}
+		}
+		if res[2] != "" {
+			// The braces mark a type
+			parameters[res[1]] = "{" + res[2] + "}"
+		} else {
+			parameters[res[1]] = res[3]
+		}
+	}
+	return
+}
+
+// Split the use of a resource type on the spaces, except the ones
+// between braces, ex: `item={Book | nil}`
+func splitResourceType(line string) (fields []string, err error) {
+	depth, field := 0, ""
+	for _, c := range line {
+		switch {
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced braces in the resource type `%s`", line)
+			}
+		case unicode.IsSpace(c) && depth == 0:
+			if field != "" {
+				fields = append(fields, field)
+			}
+			field = ""
+			continue
+		}
+		field += string(c)
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced braces in the resource type `%s`", line)
+	}
+	if field != "" {
+		fields = append(fields, field)
+	}
+	return
+}
+
+// Format a type of a resource type, the types using parameters being left untouched
+func formatResourceTypeType(t Type) (Type, []Type, error) {
+	if t == "" || strings.Contains(string(t), "<<") {
+		return t, nil, nil
+	}
+	_, precise, register_types, err := formatType(string(t))
+	return precise, register_types, err
+}
+
+// Create the RAML resource type
+func (rt ResourceType) toRAML() (res raml.ResourceType, register_types []Type, err error) {
+	res = raml.ResourceType{
+		Usage:       rt.Usage,
+		Description: rt.Description,
+	}
+	for method, rtm := range rt.Methods {
+		m := &raml.Method{Description: rtm.Description}
+
+		// Query parameters
+		if len(rtm.QueryParameters) != 0 {
+			m.QueryParameters = map[string]raml.Type{}
+			for name, p := range rtm.QueryParameters {
+				var other_ts []Type
+				p.Type, other_ts, err = formatResourceTypeType(p.Type)
+				if err != nil {
+					return
+				}
+				register_types = append(register_types, other_ts...)
+				m.QueryParameters[name], err = p.toRAML()
+				if err != nil {
+					return
+				}
+			}
+		}
+
+		// Body
+		if rtm.Body != "" {
+			t, other_ts, err := formatResourceTypeType(rtm.Body)
+			if err != nil {
+				return res, nil, err
+			}
+			register_types = append(register_types, other_ts...)
+			m.Body = raml.Body{_DEFAULT_MEDIA_TYPE: &raml.Type{Type: string(t)}}
+		}
+
+		// Response
+		if rtm.Response != "" {
+			t, other_ts, err := formatResourceTypeType(rtm.Response)
+			if err != nil {
+				return res, nil, err
+			}
+			register_types = append(register_types, other_ts...)
+			resp, err := (&Response{Type: t}).toRAML()
+			if err != nil {
+				return res, nil, err
+			}
+			m.Responses = map[raml.HTTPCode]raml.Response{200: resp}
+		}
+
+		switch method {
+		case "GET":
+			res.Get = m
+		case "PATCH":
+			res.Patch = m
+		case "PUT":
+			res.Put = m
+		case "HEAD":
+			res.Head = m
+		case "POST":
+			res.Post = m
+		case "DELETE":
+			res.Delete = m
+		case "OPTIONS":
+			res.Options = m
+		}
+	}
+	return
+}
+
+// Create the RAML resource types used by the routes, and the types they use
+func resourceTypesToRAML(index *map[string]raml.ResourceType, names []string) (register_types []Type, err error) {
+	if index == nil {
+		return nil, nil
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		rt, ok := index_resource_types[name]
+		if !ok {
+			continue
+		}
+		res, other_ts, err := rt.toRAML()
+		if err != nil {
+			return nil, fmt.Errorf("error while RAMLing resource type %s: %v", name, err)
+		}
+		register_types = append(register_types, other_ts...)
+		(*index)[name] = res
+	}
+	return
+}
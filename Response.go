@@ -2,19 +2,37 @@ package godoc2api
 
 import "github.com/florenthobein/godoc2api/raml"
 
+// Response, which body can have several media types
 type Response struct {
-	Type        Type
+	Type        Type // type of the body in the default media type
 	Description string
+	Body        Body // types of the body by media type, ex: `text/csv` => `string`
+}
+
+// Types of a body by media type, ex: `multipart/form-data` => `UploadForm`
+type Body map[string]Type
+
+// Types of the body by media type, `Type` being the one of the default media type
+func (r *Response) bodies() Body {
+	b := Body{}
+	for media_type, t := range r.Body {
+		b[media_type] = t
+	}
+	if _, ok := b[_DEFAULT_MEDIA_TYPE]; !ok && r.Type != "" {
+		b[_DEFAULT_MEDIA_TYPE] = r.Type
+	}
+	return b
 }
 
 func (r *Response) toRAML() (resp raml.Response, err error) {
-	resp = raml.Response{
-		Body: raml.Body{
-			JSON: &raml.Type{
-				Type:        string(r.Type),
-				Description: r.Description,
-			},
-		},
+	resp.Description = r.Description
+	bodies := r.bodies()
+	if len(bodies) == 0 {
+		return
+	}
+	resp.Body = raml.Body{}
+	for media_type, t := range bodies {
+		resp.Body[media_type] = &raml.Type{Type: string(t)}
 	}
 	return
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/florenthobein/godoc2api/raml"
@@ -18,18 +19,27 @@ type Route struct {
 	Callback        string
 	URIParameters   map[string]Parameter
 	QueryParameters map[string]Parameter
-	BodyParameters  map[string]Parameter
-	Response        *Response
+	Headers         map[string]Parameter
+	BodyParameters  map[string]Parameter // by media type, ex: `multipart/form-data`
+	Responses       map[int]Response     // by HTTP status
 	Examples        map[string]Example
 	Traits          map[string]Trait
 	Securities      map[string]Security
+	Scopes          map[string][]string // scopes required by the securities, identified by their tag
+	Public          bool                // not secured, whatever the securities applied by default
+	ResourceType    string              // resource type applied to the resource
+	ResourceParams  map[string]string   // parameters of the resource type, ex: `item` => `Book`
+	ResourceName    string              // display name of the resource
+	ResourceDesc    string              // description of the resource
+	Protocols       []string            // overriding the ones of the API, ex: HTTPS
 	Annotations     map[string]Annotation
 
 	_documentation *Documentation
+	_package       string
 }
 
 func (r *Route) signature() string {
-	return fmt.Sprint("%s %s", r.Method, r.Resource)
+	return fmt.Sprintf("%s %s", r.Method, r.Resource)
 }
 
 // Add and parse a tag to the Route object
@@ -129,6 +139,10 @@ func (r *Route) addTag(tag string, value interface{}) (err error) {
 		if err != nil || len(v) == 0 {
 			return err
 		}
+		v = r.resolveTypes(v)
+		if rt, err := parseRequestType(v); err == nil {
+			return r.addRequest(rt, _REQUEST_URI)
+		}
 		p, register_types, err := parseParameter(v, false)
 		if err != nil {
 			return err
@@ -156,6 +170,10 @@ func (r *Route) addTag(tag string, value interface{}) (err error) {
 		if err != nil || len(v) == 0 {
 			return err
 		}
+		v = r.resolveTypes(v)
+		if rt, err := parseRequestType(v); err == nil {
+			return r.addRequest(rt, _REQUEST_QUERY)
+		}
 		p, register_types, err := parseParameter(v, false)
 		if err != nil {
 			return err
@@ -174,6 +192,12 @@ func (r *Route) addTag(tag string, value interface{}) (err error) {
 		if err != nil || len(v) == 0 {
 			return err
 		}
+		// An eventual media type, ex: `multipart/form-data {UploadForm}`
+		_, media_type, v := parseStatusAndMediaType(v, false)
+		if media_type == "" {
+			media_type = _DEFAULT_MEDIA_TYPE
+		}
+		v = r.resolveTypes(v)
 		p, register_types, err := parseParameter(v, true)
 		if err != nil {
 			return err
@@ -181,7 +205,7 @@ func (r *Route) addTag(tag string, value interface{}) (err error) {
 		if r.BodyParameters == nil {
 			r.BodyParameters = make(map[string]Parameter)
 		}
-		r.BodyParameters[p.Name] = p
+		r.BodyParameters[media_type] = p
 		// Store a Type definition in the Documentation
 		for _, new_t := range register_types {
 			r._documentation.addType(new_t)
@@ -192,16 +216,101 @@ func (r *Route) addTag(tag string, value interface{}) (err error) {
 		if err != nil || len(v) == 0 {
 			return err
 		}
-		resp, register_types, err := parseResponse(v)
-		if err != nil {
-			return err
+		// An eventual status and media type, ex: `200 text/csv {string}`
+		status, media_type, v := parseStatusAndMediaType(v, true)
+		resp, register_types := Response{}, []Type{}
+		if strings.HasPrefix(v[0], "{") {
+			v = r.resolveTypes(v)
+			resp, register_types, err = parseResponse(v)
+			if err != nil {
+				return err
+			}
+		} else if media_type != "" {
+			return fmt.Errorf("missing the type of the response %d in %s", status, media_type)
+		} else {
+			// A response without body, ex: `204 No content`
+			resp.Description, _, err = parseDescription(v)
+			if err != nil {
+				return err
+			}
+		}
+		if media_type == "" {
+			media_type = _DEFAULT_MEDIA_TYPE
 		}
-		r.Response = &resp
+		r.addResponse(status, media_type, resp)
 		// Store a Type definition in the Documentation
 		for _, new_t := range register_types {
 			r._documentation.addType(new_t)
 		}
 		break
+	case TAG_REQUEST:
+		var rt reflect.Type
+		if kind == reflect.Struct || kind == reflect.Ptr {
+			rt = reflect.TypeOf(value)
+			for rt.Kind() == reflect.Ptr {
+				rt = rt.Elem()
+			}
+			if rt.Kind() != reflect.Struct {
+				return fmt.Errorf("wrong kind for the tag %s: struct expected", tag)
+			}
+		} else {
+			v, err := checkArray()
+			if err != nil || len(v) == 0 {
+				return err
+			}
+			rt, err = parseRequestType(v)
+			if err != nil {
+				return err
+			}
+		}
+		return r.addRequest(rt, "")
+	case TAG_PUBLIC:
+		// A bool, or nothing from a comment, ex: `@public`
+		r.Public = true
+		if kind == reflect.Bool {
+			r.Public, _ = checkBool()
+		}
+		break
+	case TAG_RESOURCE_DESCRIPTION:
+		v, err := checkArray()
+		if err != nil || len(v) == 0 {
+			return err
+		}
+		r.ResourceDesc, r.ResourceName, err = parseDescription(append([]string{}, v...))
+		return err
+	case TAG_PROTOCOLS:
+		v, err := checkArray()
+		if err != nil || len(v) == 0 {
+			return err
+		}
+		r.Protocols, err = parseProtocols(v)
+		return err
+	case TAG_RESOURCE_TYPE:
+		v, err := checkArray()
+		if err != nil || len(v) == 0 {
+			return err
+		}
+		name, parameters, err := parseResourceType(strings.Join(v, " "))
+		if err != nil {
+			return err
+		}
+		// The types are formatted, ex: `{Book}` => `Book`
+		for k, p := range parameters {
+			if !strings.HasPrefix(p, "{") {
+				continue
+			}
+			resolved := resolveCommentTypes(r._package, p)
+			_, precise, register_types, err := formatType(resolved[1 : len(resolved)-1])
+			if err != nil {
+				return fmt.Errorf("%v for the parameter %s of the resource type %s", err, k, name)
+			}
+			parameters[k] = groupUnion(string(precise))
+			for _, new_t := range register_types {
+				r._documentation.addType(new_t)
+			}
+		}
+		r.ResourceType, r.ResourceParams = name, parameters
+		break
 	case TAG_EXAMPLES:
 		vs, err := checkArrayArray()
 		if err != nil || len(vs) == 0 {
@@ -234,25 +343,51 @@ func (r *Route) addTag(tag string, value interface{}) (err error) {
 				parseAnnotation(tag, value)
 				break
 			case _TAG_TYPE_TRAIT:
-				// todo
-				parseTrait(tag, value)
-				break
-			case _TAG_TYPE_SECURITY:
-				v, err := checkBool()
+				if kind == reflect.Bool {
+					if v, _ := checkBool(); !v {
+						break
+					}
+				}
+				t, err := parseTrait(tag, value)
 				if err != nil {
 					return err
 				}
-				if !v {
-					break
+				if r.Traits == nil {
+					r.Traits = map[string]Trait{}
+				}
+				r.Traits[tag] = t
+				break
+			case _TAG_TYPE_SECURITY:
+				// A bool, or the scopes required, ex: `@oauth books:read books:write`
+				scopes := []string{}
+				if kind == reflect.Bool {
+					if v, _ := checkBool(); !v {
+						break
+					}
+				} else {
+					v, err := checkArray()
+					if err != nil {
+						return err
+					}
+					scopes = parseScopes(v)
 				}
 				s, err := parseSecurity(tag)
 				if err != nil {
 					return err
 				}
+				if err := s.checkScopes(scopes); err != nil {
+					return fmt.Errorf("%v for the security %s", err, tag)
+				}
 				if r.Securities == nil {
 					r.Securities = map[string]Security{}
 				}
 				r.Securities[tag] = s
+				if len(scopes) != 0 {
+					if r.Scopes == nil {
+						r.Scopes = map[string][]string{}
+					}
+					r.Scopes[tag] = scopes
+				}
 				break
 			default:
 				err = fmt.Errorf("unkown tag type %d for %s", tag_type, tag)
@@ -264,6 +399,67 @@ func (r *Route) addTag(tag string, value interface{}) (err error) {
 	return
 }
 
+// Resolve the go types referenced by the definition of a tag,
+// in the package of the handler, ex: `{models.Book}`
+func (r *Route) resolveTypes(v []string) []string {
+	if len(v) == 0 {
+		return v
+	}
+	res := append([]string{}, v...)
+	res[0] = resolveCommentTypes(r._package, res[0])
+	return res
+}
+
+// Add the parameters described by a request struct,
+// eventually only the ones of a location (uri, query or header)
+func (r *Route) addRequest(rt reflect.Type, only string) error {
+	ps, has_body, register_types, err := parseRequest(rt)
+	if err != nil {
+		return err
+	}
+	for in, list := range ps {
+		if only != "" && in != only {
+			continue
+		}
+		var index *map[string]Parameter
+		switch in {
+		case _REQUEST_URI:
+			index = &r.URIParameters
+		case _REQUEST_QUERY:
+			index = &r.QueryParameters
+		case _REQUEST_HEADER:
+			index = &r.Headers
+		}
+		if *index == nil {
+			*index = make(map[string]Parameter)
+		}
+		for _, p := range list {
+			(*index)[p.Name] = p
+		}
+	}
+
+	// The struct itself is the body
+	if has_body && only == "" {
+		discoverType(rt)
+		name := goTypeName(rt)
+		_, t, other_ts, err := formatType(name)
+		if err != nil {
+			return err
+		}
+		register_types = append(register_types, other_ts...)
+		if r.BodyParameters == nil {
+			r.BodyParameters = make(map[string]Parameter)
+		}
+		r.BodyParameters[_DEFAULT_MEDIA_TYPE] = Parameter{Name: name, Type: t}
+	}
+
+	// Store a Type definition in the Documentation
+	for _, new_t := range register_types {
+		r._documentation.addType(new_t)
+	}
+	return nil
+}
+
 // Check if a route can be described
 func (r *Route) checkViability() error {
 	if r.Method == "" {
@@ -351,11 +547,60 @@ func (r *Route) fillToRAML(index *map[string]raml.Resource) (err error) {
 	}
 	res.Methods = append(res.Methods, m)
 
-	// Traits
-	// todo
+	// Display name and description
+	if r.ResourceName != "" {
+		res.DisplayName = r.ResourceName
+	}
+	if r.ResourceDesc != "" {
+		res.Description = r.ResourceDesc
+	}
 
-	// Securities
-	// todo
+	// Resource type, the methods it defines being only completed by the route
+	if r.ResourceType != "" {
+		if res.Type != nil && !reflect.DeepEqual(res.Type, r.resourceTypeToRAML()) {
+			warn("several resource types for the resource %s", r.Resource)
+		}
+		res.Type = r.resourceTypeToRAML()
+		if rtm, ok := index_resource_types[r.ResourceType].Methods[r.Method]; ok {
+			// The body and the response of the route are kept when they differ
+			if b, ok := m.Body[_DEFAULT_MEDIA_TYPE]; ok && rtm.Body != "" {
+				if r.isResourceTypeType(rtm.Body, b) {
+					delete(m.Body, _DEFAULT_MEDIA_TYPE)
+				} else {
+					warn("the body of %s %s differs from the one of its resource type %s", r.Method, r.Resource, r.ResourceType)
+				}
+			}
+			if resp, ok := m.Responses[200]; ok && rtm.Response != "" {
+				if b, ok := resp.Body[_DEFAULT_MEDIA_TYPE]; ok {
+					if r.isResourceTypeType(rtm.Response, b) {
+						delete(resp.Body, _DEFAULT_MEDIA_TYPE)
+					} else {
+						warn("the response of %s %s differs from the one of its resource type %s", r.Method, r.Resource, r.ResourceType)
+					}
+				}
+				if len(resp.Body) == 0 && resp.Description == "" {
+					delete(m.Responses, 200)
+				}
+			}
+			if len(m.Body) == 0 {
+				m.Body = nil
+			}
+			if len(m.Responses) == 0 {
+				m.Responses = nil
+			}
+		}
+	}
+
+	// Traits and securities applied by default
+	if r._documentation != nil {
+		res.Is = r._documentation.prefixTraits(r.Resource)
+		if securities := r._documentation.prefixSecurities(r.Resource); len(securities) != 0 {
+			res.SecuredBy = []interface{}{}
+			for _, security := range securities {
+				res.SecuredBy = append(res.SecuredBy, security)
+			}
+		}
+	}
 
 	// Annotations
 	// todo
@@ -365,6 +610,84 @@ func (r *Route) fillToRAML(index *map[string]raml.Resource) (err error) {
 	return nil
 }
 
+// Add a response to the route, completing the one of the same status
+// with another media type
+func (r *Route) addResponse(status int, media_type string, resp Response) {
+	if r.Responses == nil {
+		r.Responses = map[int]Response{}
+	}
+	existing, ok := r.Responses[status]
+	if !ok {
+		existing = Response{Body: Body{}}
+	}
+	if resp.Type != "" {
+		existing.Body[media_type] = resp.Type
+	}
+	if resp.Description != "" {
+		existing.Description = resp.Description
+	}
+	r.Responses[status] = existing
+}
+
+// Resource type of the route's resource, with its parameters
+func (r *Route) resourceTypeToRAML() interface{} {
+	if len(r.ResourceParams) == 0 {
+		return r.ResourceType
+	}
+	return map[string]map[string]string{r.ResourceType: r.ResourceParams}
+}
+
+// Check if a type of the route is the one of its resource type,
+// its parameters being replaced, ex: `<<item>>[]` for `Book[]`
+func (r *Route) isResourceTypeType(rt_type Type, t *raml.Type) bool {
+	s := string(rt_type)
+	for k, v := range r.ResourceParams {
+		s = strings.Replace(s, "<<"+k+">>", groupUnion(v), -1)
+	}
+	if t == nil || strings.Contains(s, "<<") {
+		return false
+	}
+	_, precise, _, err := formatType(s)
+	return err == nil && string(precise) == fmt.Sprint(t.Type)
+}
+
+// Group a union in parentheses, so that it can be substituted in a type,
+// ex: `(Book | nil)` for `<<item>>[]`
+func groupUnion(s string) string {
+	depth := 0
+	for i, c := range s {
+		switch {
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 && i == len(s)-1 && s[0] == '(' {
+				// Already grouped, ex: `(Book | nil)`
+				return s
+			}
+		case c == '|' && depth == 0:
+			return "(" + s + ")"
+		}
+	}
+	return s
+}
+
+// Tags of the securities of the route, the ones applied by default included
+func (r *Route) securities() []string {
+	tags := []string{}
+	for tag := range r.Securities {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	if len(tags) != 0 || r.Public || r._documentation == nil {
+		return tags
+	}
+	if tags = r._documentation.prefixSecurities(r.Resource); len(tags) != 0 {
+		return tags
+	}
+	return r._documentation.defaultSecurities()
+}
+
 func (r *Route) _parametersToRAML(ps map[string]Parameter) (parameters map[string]raml.Type, err error) {
 	if ps == nil {
 		return nil, nil
@@ -389,17 +712,39 @@ func (r *Route) _methodToRAML() (*raml.Method, error) {
 		return nil, err
 	}
 
+	headers, err := r._parametersToRAML(r.Headers)
+	if err != nil {
+		return nil, err
+	}
+
 	m := raml.Method{
 		Name:            r.Name,
 		Description:     r.Description,
 		QueryParameters: queryParameters,
+		Headers:         headers,
+		Protocols:       r.Protocols,
 	}
 
 	// Security Schemes
+	if r.Public && len(r.Securities) == 0 {
+		// Explicitly not secured
+		m.SecuredBy = []interface{}{nil}
+	}
 	if r.Securities != nil {
-		m.SecuredBy = []string{}
-		for security, _ := range r.Securities {
-			m.SecuredBy = append(m.SecuredBy, security)
+		m.SecuredBy = []interface{}{}
+		securities := []string{}
+		for security := range r.Securities {
+			securities = append(securities, security)
+		}
+		sort.Strings(securities)
+		for _, security := range securities {
+			if scopes, ok := r.Scopes[security]; ok {
+				m.SecuredBy = append(m.SecuredBy, raml.SecuredBy{
+					security: raml.SecuredByParameters{Scopes: scopes},
+				})
+			} else {
+				m.SecuredBy = append(m.SecuredBy, security)
+			}
 		}
 	}
 
@@ -409,44 +754,44 @@ func (r *Route) _methodToRAML() (*raml.Method, error) {
 		for trait, _ := range r.Traits {
 			m.Is = append(m.Is, trait)
 		}
+		sort.Strings(m.Is)
 	}
 
-	// Bodies
+	// Bodies, by media type
 	if r.BodyParameters != nil {
-		for _, p := range r.BodyParameters {
-			m.Body = &raml.Body{
-				JSON: &raml.Type{
-					Type:        p.Type,
-					Description: p.Description,
-				},
+		m.Body = raml.Body{}
+		for media_type, p := range r.BodyParameters {
+			m.Body[media_type] = &raml.Type{
+				Type:        p.Type,
+				Description: p.Description,
 			}
+		}
 
-			// Examples
-			if len(r.Examples) != 0 {
-				(*(*m.Body).JSON).Examples = map[string]interface{}{}
-				for k, e := range r.Examples {
-					ex, err := e.toRAMLQuery()
-					if err != nil {
-						return nil, err
-					}
-					if ex == nil {
-						continue
-					}
-					(*(*m.Body).JSON).Examples[k] = *ex
+		// Examples, in the default media type
+		if t, ok := m.Body[_DEFAULT_MEDIA_TYPE]; ok && len(r.Examples) != 0 {
+			t.Examples = map[string]interface{}{}
+			for k, e := range r.Examples {
+				ex, err := e.toRAMLQuery()
+				if err != nil {
+					return nil, err
+				}
+				if ex == nil {
+					continue
 				}
+				t.Examples[k] = *ex
 			}
 		}
 	}
 
-	// Response
-	if r.Response != nil {
-		resp, err := (*r.Response).toRAML()
+	// Responses, by status
+	for status, r_resp := range r.Responses {
+		resp, err := r_resp.toRAML()
 		if err != nil {
 			return nil, err
 		}
-		// Examples
-		if len(r.Examples) != 0 {
-			(*resp.Body.JSON).Examples = map[string]interface{}{}
+		// Examples, in the default media type of the successful response
+		if t, ok := resp.Body[_DEFAULT_MEDIA_TYPE]; ok && status == 200 && len(r.Examples) != 0 {
+			t.Examples = map[string]interface{}{}
 			for k, e := range r.Examples {
 				ex, err := e.toRAMLResponse()
 				if err != nil {
@@ -455,12 +800,13 @@ func (r *Route) _methodToRAML() (*raml.Method, error) {
 				if ex == nil {
 					continue
 				}
-				(*resp.Body.JSON).Examples[k] = *ex
+				t.Examples[k] = *ex
 			}
 		}
-		m.Responses = map[raml.HTTPCode]raml.Response{
-			200: resp,
+		if m.Responses == nil {
+			m.Responses = map[raml.HTTPCode]raml.Response{}
 		}
+		m.Responses[raml.HTTPCode(status)] = resp
 	}
 
 	return &m, nil
@@ -0,0 +1,134 @@
+package godoc2api
+
+import "net/http"
+
+// Route of a gorilla/mux router, satisfied by `*mux.Route`.
+type MuxRoute interface {
+	GetPathTemplate() (string, error)
+	GetMethods() ([]string, error)
+	GetHandler() http.Handler
+}
+
+// Wrapper around a `http.ServeMux` that documents every route it registers.
+type ServeMux struct {
+	*http.ServeMux
+	documentation *Documentation
+	errors        []error
+}
+
+// Add a route discovered in the route table of a router.
+//
+// The method and the path template given by the router take precedence over
+// the `@method` and `@resource` tags of the handler's comment, the rest of the
+// route is described as usual.
+//
+// Example:
+//	// Define a http handler
+//	// @response {MyObject}
+//	func MyHandler(http.ResponseWriter, *http.Request) { ... }
+//
+//	...
+//
+//	d := godoc2api.Documentation{}
+//	d.AddRouterRoute("GET", "/myroute/{id:[0-9]+}", MyHandler)
+func (d *Documentation) AddRouterRoute(method, path string, handler interface{}) error {
+	resource, err := parseRouterPath(path)
+	if err != nil {
+		warn("%v (%s)", err, path)
+		return err
+	}
+	return d.addRoute(handler, method, resource)
+}
+
+// Add a route of a gorilla/mux router.
+//
+// Example:
+//	r := mux.NewRouter()
+//	r.HandleFunc("/books/{id}", GetBook).Methods("GET")
+//
+//	d := godoc2api.Documentation{}
+//	r.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+//		return d.AddMuxRoute(route)
+//	})
+func (d *Documentation) AddMuxRoute(route MuxRoute) error {
+	handler := route.GetHandler()
+	if handler == nil {
+		// Subrouters and prefixes don't have any handler
+		return nil
+	}
+	path, err := route.GetPathTemplate()
+	if err != nil {
+		return err
+	}
+	methods, err := route.GetMethods()
+	if err != nil {
+		// No method matcher, let the comment define it
+		return d.AddRouterRoute("", path, handler)
+	}
+	for _, method := range methods {
+		if err := d.AddRouterRoute(method, path, handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Walk function for a chi router. The first route that can't be documented
+// stops the walk, its error being returned by `chi.Walk`.
+//
+// Example:
+//	r := chi.NewRouter()
+//	r.Get("/books/{id}", GetBook)
+//
+//	d := godoc2api.Documentation{}
+//	chi.Walk(r, d.ChiWalkFunc)
+func (d *Documentation) ChiWalkFunc(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+	return d.AddRouterRoute(method, route, handler)
+}
+
+// Wrap a `http.ServeMux` so that the routes registered through it are
+// added to the documentation. Patterns can use the Go 1.22 syntax, like
+// `GET /books/{id}`.
+//
+// Example:
+//	d := godoc2api.Documentation{}
+//	mux := d.ServeMux(http.NewServeMux())
+//	mux.HandleFunc("GET /books/{id}", GetBook)
+//	if errs := mux.Errors(); len(errs) != 0 { ... }
+//	http.ListenAndServe(":8080", mux)
+func (d *Documentation) ServeMux(mux *http.ServeMux) *ServeMux {
+	if mux == nil {
+		mux = http.NewServeMux()
+	}
+	return &ServeMux{ServeMux: mux, documentation: d}
+}
+
+// Register the handler for the given pattern and document it.
+func (m *ServeMux) Handle(pattern string, handler http.Handler) {
+	m.ServeMux.Handle(pattern, handler)
+	m.document(pattern, handler)
+}
+
+// Register the handler function for the given pattern and document it.
+func (m *ServeMux) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	m.ServeMux.HandleFunc(pattern, handler)
+	m.document(pattern, http.HandlerFunc(handler))
+}
+
+// Errors of the routes that couldn't be documented, the registration
+// of the handlers by the mux being unaffected
+func (m *ServeMux) Errors() []error {
+	return m.errors
+}
+
+func (m *ServeMux) document(pattern string, handler http.Handler) {
+	method, path, err := parseServeMuxPattern(pattern)
+	if err != nil {
+		warn("%v (%s)", err, pattern)
+		m.errors = append(m.errors, err)
+		return
+	}
+	if err := m.documentation.AddRouterRoute(method, path, handler); err != nil {
+		m.errors = append(m.errors, err)
+	}
+}
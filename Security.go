@@ -1,6 +1,10 @@
 package godoc2api
 
-import "github.com/florenthobein/godoc2api/raml"
+import (
+	"fmt"
+
+	"github.com/florenthobein/godoc2api/raml"
+)
 
 // Security schemes
 const (
@@ -31,14 +35,45 @@ type Security struct {
 	Description     string
 	Headers         map[string]Parameter
 	QueryParameters map[string]Parameter
-	QueryString     Parameter
-	// Responses       []Response
-	// Settings
+	QueryString     Parameter        // exclusive with QueryParameters, ex: `Parameter{Type: "ApiKeyQuery"}`
+	Responses       map[int]Response // the responses of the scheme by HTTP status, ex: 401
+	OAuth1          *OAuth1Settings  // only for SECURITY_OAUTH_1
+	OAuth2          *OAuth2Settings  // only for SECURITY_OAUTH_2
+}
+
+// Settings of an OAuth 1.0 security scheme
+type OAuth1Settings struct {
+	RequestTokenURI     string
+	AuthorizationURI    string
+	TokenCredentialsURI string
+	Signatures          []string // HMAC-SHA1, RSA-SHA1 or PLAINTEXT
+}
+
+// Settings of an OAuth 2.0 security scheme
+type OAuth2Settings struct {
+	AuthorizationURI    string
+	AccessTokenURI      string
+	AuthorizationGrants []string // authorization_code, password, client_credentials, implicit or an absolute URI
+	Scopes              []string // the scopes that can be required by the routes, ex: `@auth books:write`
 }
 
 // Configure a new security scheme.
 // All the routes that declare the tag `tag_name` will be considered
 // secured by this scheme.
+//
+// The routes secured by an OAuth 2.0 scheme can require scopes,
+// ex: `@oauth books:read books:write`.
+//
+// Example
+//	DefineSecurity("oauth", Security{
+//		Type: SECURITY_OAUTH_2,
+//		OAuth2: &OAuth2Settings{
+//			AuthorizationURI:    "https://auth.example.com/authorize",
+//			AccessTokenURI:      "https://auth.example.com/token",
+//			AuthorizationGrants: []string{"authorization_code"},
+//			Scopes:              []string{"books:read", "books:write"},
+//		},
+//	})
 func DefineSecurity(tag_name string, s Security) {
 	// Store the keyword
 	reserveTag(tag_name, _TAG_TYPE_SECURITY)
@@ -50,12 +85,19 @@ func DefineSecurity(tag_name string, s Security) {
 }
 
 // func (s *Security) fillToRAML(index *map[string]raml.SecurityScheme) error {
-func securitiesToRAML(index *map[string]raml.SecurityScheme) error {
+// Only the schemes that are used are rendered, with the types they reference.
+func securitiesToRAML(index *map[string]raml.SecurityScheme, used map[string]bool) error {
 	if index == nil {
 		return nil
 	}
 
 	for key, s := range index_securities {
+		if !used[key] {
+			continue
+		}
+		if s.QueryString.Type != "" && s.QueryParameters != nil {
+			return fmt.Errorf("the query string and the query parameters of the security %s are exclusive", key)
+		}
 		type_name := securities_types_names_default[s.Type]
 		if s.TypeName != "" && len(s.TypeName) > 2 && s.TypeName[0] == 'x' && s.TypeName[1] == '-' {
 			type_name = s.TypeName
@@ -81,7 +123,22 @@ func securitiesToRAML(index *map[string]raml.SecurityScheme) error {
 				}
 			}
 		}
-		// todo query string
+		if s.QueryString.Type != "" {
+			query_string, _, err := s.queryStringToRAML()
+			if err != nil {
+				return fmt.Errorf("error while RAMLing the query string of the security %s: %v", key, err)
+			}
+			has_description = true
+			description.QueryString = query_string
+		}
+		if len(s.Responses) != 0 {
+			responses, _, err := s.responsesToRAML()
+			if err != nil {
+				return fmt.Errorf("error while RAMLing the responses of the security %s: %v", key, err)
+			}
+			has_description = true
+			description.Responses = responses
+		}
 		ss := raml.SecurityScheme{
 			Type:        type_name,
 			Description: s.Description,
@@ -89,8 +146,115 @@ func securitiesToRAML(index *map[string]raml.SecurityScheme) error {
 		if has_description {
 			ss.DescribedBy = description
 		}
+		ss.Settings = s.settingsToRAML()
 		(*index)[key] = ss
 	}
 
 	return nil
 }
+
+// Types used by the security schemes that are used, to register in the documentation
+func securitiesTypes(used map[string]bool) (register_types []Type, err error) {
+	for key, s := range index_securities {
+		if !used[key] {
+			continue
+		}
+		_, query_types, err := s.queryStringToRAML()
+		if err != nil {
+			return nil, fmt.Errorf("wrong query string for the security %s: %v", key, err)
+		}
+		_, response_types, err := s.responsesToRAML()
+		if err != nil {
+			return nil, fmt.Errorf("wrong responses for the security %s: %v", key, err)
+		}
+		register_types = append(register_types, query_types...)
+		register_types = append(register_types, response_types...)
+	}
+	return
+}
+
+// Create the RAML type of the query string of the scheme
+func (s Security) queryStringToRAML() (t raml.Type, register_types []Type, err error) {
+	if s.QueryString.Type == "" {
+		return
+	}
+	p := s.QueryString
+	var precise Type
+	_, precise, register_types, err = formatType(string(p.Type))
+	if err != nil {
+		return
+	}
+	p.Type = precise
+	t, err = p.toRAML()
+	return
+}
+
+// Create the RAML responses of the scheme, by HTTP status
+func (s Security) responsesToRAML() (responses map[raml.HTTPCode]raml.Response, register_types []Type, err error) {
+	if len(s.Responses) == 0 {
+		return
+	}
+	responses = map[raml.HTTPCode]raml.Response{}
+	for code, r := range s.Responses {
+		bodies := Body{}
+		for media_type, t := range r.bodies() {
+			_, precise, other_ts, err := formatType(string(t))
+			if err != nil {
+				return nil, nil, fmt.Errorf("wrong type for the response %d: %v", code, err)
+			}
+			register_types = append(register_types, other_ts...)
+			bodies[media_type] = precise
+		}
+		r.Type, r.Body = "", bodies
+		resp, err := r.toRAML()
+		if err != nil {
+			return nil, nil, err
+		}
+		responses[raml.HTTPCode(code)] = resp
+	}
+	return
+}
+
+// Create the RAML settings of an OAuth security scheme
+func (s Security) settingsToRAML() *raml.SecuritySchemeSettings {
+	switch {
+	case s.Type == SECURITY_OAUTH_1 && s.OAuth1 != nil:
+		return &raml.SecuritySchemeSettings{
+			RequestTokenUri:     s.OAuth1.RequestTokenURI,
+			AuthorizationUri:    s.OAuth1.AuthorizationURI,
+			TokenCredentialsUri: s.OAuth1.TokenCredentialsURI,
+			Signatures:          s.OAuth1.Signatures,
+		}
+	case s.Type == SECURITY_OAUTH_2 && s.OAuth2 != nil:
+		return &raml.SecuritySchemeSettings{
+			AuthorizationUri:    s.OAuth2.AuthorizationURI,
+			AccessTokenUri:      s.OAuth2.AccessTokenURI,
+			AuthorizationGrants: s.OAuth2.AuthorizationGrants,
+			Scopes:              s.OAuth2.Scopes,
+		}
+	}
+	return nil
+}
+
+// Check the scopes required by a route, against the ones of the scheme
+func (s Security) checkScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return nil
+	}
+	if s.Type != SECURITY_OAUTH_2 {
+		return fmt.Errorf("scopes can only be required by an OAuth 2.0 security scheme")
+	}
+	if s.OAuth2 == nil || len(s.OAuth2.Scopes) == 0 {
+		return nil
+	}
+	for _, scope := range scopes {
+		found := false
+		for _, known := range s.OAuth2.Scopes {
+			found = found || known == scope
+		}
+		if !found {
+			return fmt.Errorf("unknown scope `%s`", scope)
+		}
+	}
+	return nil
+}
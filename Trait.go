@@ -5,16 +5,31 @@ import "github.com/florenthobein/godoc2api/raml"
 // Trait, mirror of the RAML equivalent
 type Trait struct{}
 
+// Registry of traits
+var index_traits map[string]Trait
+
 // Configure a new trait.
 // All the routes that declare the tag tag_name will be considered
 // using this trait.
 func DefineTrait(tag_name string, t interface{}) {
 	// Store the keyword
 	reserveTag(tag_name, _TAG_TYPE_TRAIT)
-
-	// todo
+	// Store in the index
+	if index_traits == nil {
+		index_traits = map[string]Trait{}
+	}
+	index_traits[tag_name] = Trait{} // todo
 }
 
-func (t *Trait) fillToRAML(index *map[string]raml.Trait) error {
+// Create the RAML traits that are used by the routes
+func traitsToRAML(index *map[string]raml.Trait, used map[string]bool) error {
+	if index == nil {
+		return nil
+	}
+	for key := range index_traits {
+		if used[key] {
+			(*index)[key] = raml.Trait{}
+		}
+	}
 	return nil
 }
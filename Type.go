@@ -3,7 +3,6 @@ package godoc2api
 import (
 	"fmt"
 	"reflect"
-	"regexp"
 	"strings"
 
 	"github.com/fatih/structs"
@@ -14,9 +13,6 @@ import (
 // Registry of type definitions
 var index_types map[string]TypeDefinition
 
-// Regex to match maps
-const _PARSE_MAP = `^map\[([^\]]+)\](.+)$`
-
 // A Type is a global definition of a parameter's type.
 // Every type that is not a scalar should generate
 // a type definition.
@@ -29,17 +25,21 @@ type TypeDefinition struct {
 	aliasFor     *TypeDefinition
 	nameRAMLType string
 	reflectType  *reflect.Type
+	sourceType   *sourceRef
 	properties   map[string]interface{}
 	mapKey       string
 	mapValue     string
+	goName       string
+	discovered   bool
+	builder      TypeBuilder
 }
 
 // Configure a new type definition.
 //
-// The objects used as inputs and outputs of the API
-// have to match a defined type.
-// This is mandatory, as go doesn't have a global type registry that would allow
-// to create a variable out of its kind's name.
+// The types referenced in the comments are discovered by reading the sources
+// of the handler's package and of its imports, and the types used by a defined type
+// are discovered by reflection.
+// Defining a type is only needed to rename it or to override its discovered definition.
 //
 // Example
 //
@@ -60,11 +60,23 @@ func DefineType(name string, obj interface{}) {
 		name:        name,
 		reflectType: &ref,
 	}
+	if enum, ok := enumTypeDefinition(name, ref); ok {
+		td = enum
+	}
+	elem := ref
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	td.goName = goTypeName(elem)
 	index_types[name] = td
 	index_types[true_name] = TypeDefinition{
 		name:     true_name,
 		aliasFor: &td,
 	}
+	index_types[td.goName] = TypeDefinition{
+		name:     td.goName,
+		aliasFor: &td,
+	}
 }
 
 // Configure a new type definition already formated with standard RAML data types.
@@ -82,6 +94,48 @@ func DefineTypeRAML(name, raml_type string, properties map[string]interface{}) {
 	}
 }
 
+// Create the definition of a named scalar type by reading the constants
+// of its package, their comments are used to describe the values
+func enumTypeDefinition(name string, rt reflect.Type) (td TypeDefinition, ok bool) {
+	if rt.PkgPath() == "" || rt.Name() == "" {
+		return
+	}
+	is_scalar, raml_type := isScalar(rt.Kind().String())
+	if !is_scalar {
+		return
+	}
+	return sourceEnumDefinition(name, rt.PkgPath(), rt.Name(), raml_type)
+}
+
+// Create the definition of a named scalar type declared in a package,
+// if constants are declared with it
+func sourceEnumDefinition(name, pkg_path, type_name, raml_type string) (td TypeDefinition, ok bool) {
+	values, comments := sourceEnumValues(pkg_path, type_name)
+	if len(values) == 0 {
+		return
+	}
+	_, doc := sourceTypeSpec(pkg_path, type_name)
+	description := strings.Join(strings.Fields(doc.Text()), " ")
+	list := []string{}
+	for i, v := range values {
+		if comments[i] != "" {
+			list = append(list, fmt.Sprintf("- `%v`: %s", v, comments[i]))
+		}
+	}
+	if len(list) != 0 {
+		description = strings.Trim(description+"\n\n"+strings.Join(list, "\n"), "\n")
+	}
+	properties := map[string]interface{}{"enum": values}
+	if description != "" {
+		properties["description"] = description
+	}
+	return TypeDefinition{
+		name:         name,
+		nameRAMLType: raml_type,
+		properties:   properties,
+	}, true
+}
+
 func defineTypeMap(name, key, value string) *TypeDefinition {
 	if index_types == nil {
 		index_types = make(map[string]TypeDefinition)
@@ -159,49 +213,97 @@ func isDefinedType(name string) (t TypeDefinition, ok bool) {
 }
 
 func formatMapName(key, val string) string {
-	key = strings.Replace(key, "[]", "Array", -1)
-	val = strings.Replace(strings.Replace(val,
-		"[]", "Array", -1),
-		" ", "", -1)
-	_, k, _, _ := formatType(key)
-	_, v, _, _ := formatType(val)
-	// return fmt.Sprintf("map_%s_%s", key, val)
-	return fmt.Sprintf("map_%s_%s", string(k), string(v))
+	_, k, _, _ := formatType(strings.Replace(key, " ", "", -1))
+	_, v, _, _ := formatType(strings.Replace(val, " ", "", -1))
+	return fmt.Sprintf("map_%s_%s", typeNamePart(k), typeNamePart(v))
 }
 
-// Given a type, format it to the RAML Data TypeDefinition format
+// Write a type as a part of a type name: arrays are prefixed,
+// ex: `Book[]` => `ArrayBook`, and unions are joined, ex: `Cat | Dog` => `CatOrDog`
+func typeNamePart(t Type) string {
+	s, prefix := string(t), ""
+	for strings.HasSuffix(s, "[]") {
+		s, prefix = strings.Trim(s[:len(s)-2], "()"), prefix+"Array"
+	}
+	return prefix + strings.Replace(s, " | ", "Or", -1)
+}
+
+// Given a type expression, format it to the RAML Data TypeDefinition format
 // and add specify if it should be registired in Types.
 // Examples:
 // 		interface{}					=> any		any											nil
 // 		string							=> scalar	string									nil
 // 		*time.Time					=> scalar	datetime								nil
-// 		int16								=> scalar	number									nil
+// 		int16								=> scalar	integer									nil
+// 		[]byte							=> scalar	string									nil
 // 		map[string]MyObject	=> object	map_string_boolean			[]Type{"MyObject"}
 // 		MyObject						=> object	MyObject								[]Type{"MyObject"}
 // 		[]MyObject					=> array	MyObject[]							[]Type{"MyObject"}
 // 		map[string][]bool		=> array	map_string_Arrayboolean	nil
+// 		(string | MyObject)[]	=> array	(string | MyObject)[]	[]Type{"MyObject"}
 func formatType(name string) (global string, precise Type, register_types []Type, err error) {
+	if strings.Trim(name, " \t") == "" {
+		return "nil", Type("nil"), nil, nil
+	}
+	e, err := parseTypeExpr(name)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return formatTypeExpr(e)
+}
 
-	// Just to return a correct name
-	strs := regexp.MustCompile(` | `).Split(name, -1)
-	if len(strs) > 1 {
+// Format a parsed type expression, see `formatType`
+func formatTypeExpr(e *typeExpr) (global string, precise Type, register_types []Type, err error) {
+	switch e.kind {
+	case _EXPR_UNION:
 		register_types = []Type{}
-		for i, str := range strs {
-			if str == "|" {
-				continue
-			}
-			_, v, other_ts, _ := formatType(str)
-			for _, other_t := range other_ts {
-				register_types = append(register_types, other_t)
+		members := []string{}
+		for _, item := range e.items {
+			_, v, other_ts, err := formatTypeExpr(item)
+			if err != nil {
+				return "", "", nil, err
 			}
-			strs[i] = string(v)
+			register_types = append(register_types, other_ts...)
+			members = append(members, string(v))
 		}
-		precise = Type(strings.Join(strs, " "))
-		return
+		return "", Type(strings.Join(members, " | ")), register_types, nil
+
+	case _EXPR_POINTER:
+		return formatTypeExpr(e.items[0])
+
+	case _EXPR_ARRAY:
+		if is_scalar, name := isScalar(e.String()); is_scalar {
+			// ex: []byte
+			return "scalar", Type(name), nil, nil
+		}
+		_, precise, register_types, err = formatTypeExpr(e.items[0])
+		if strings.Contains(string(precise), " | ") && !strings.HasSuffix(string(precise), "[]") {
+			// ex: (string | Person)[]
+			precise = "(" + precise + ")"
+		}
+		return "array", Type(string(precise) + "[]"), register_types, err
+
+	case _EXPR_MAP:
+		register_types = []Type{Type(e.String())}
+		_, k, other_ts, _ := formatTypeExpr(e.items[0])
+		register_types = append(register_types, other_ts...)
+		_, v, other_ts, _ := formatTypeExpr(e.items[1])
+		register_types = append(register_types, other_ts...)
+		name := formatMapName(e.items[0].String(), e.items[1].String())
+		defineTypeMap(name, string(k), string(v))
+		debug("creation of type map %s", name)
+		return "object", Type(name), register_types, nil
 	}
 
-	if name == "" {
-		return "nil", Type("nil"), nil, nil
+	name := e.name
+
+	// Instance of a generic type, ex: Page[Book]
+	if len(e.items) > 0 {
+		full, err := discoverGenericType(e)
+		if err != nil {
+			return "", "", nil, err
+		}
+		name = full
 	}
 
 	if name == "interface{}" {
@@ -223,37 +325,6 @@ func formatType(name string) (global string, precise Type, register_types []Type
 		return
 	}
 
-	// If it's a pointer
-	if len(name) > 1 && name[0:1] == "*" {
-		global, precise, register_types, err = formatType(name[1:])
-		return
-	}
-
-	// If it's a slice
-	if len(name) > 2 && name[0:2] == "[]" {
-		global = "array"
-		_, precise, register_types, err = formatType(name[2:])
-		precise = Type(string(precise) + "[]")
-		return
-	}
-
-	// If it's a map
-	if res := regexp.MustCompile(_PARSE_MAP).FindStringSubmatch(name); len(res) > 2 {
-		register_types = []Type{Type(name)}
-		_, k, other_ts, _ := formatType(res[1])
-		for _, other_t := range other_ts {
-			register_types = append(register_types, other_t)
-		}
-		_, v, other_ts, _ := formatType(res[2])
-		for _, other_t := range other_ts {
-			register_types = append(register_types, other_t)
-		}
-		name = formatMapName(res[1], res[2])
-		defineTypeMap(name, string(k), string(v))
-		debug("creation of type map %s", name)
-		return "object", Type(name), register_types, nil
-	}
-
 	// If it's a scalar
 	if is_scalar, name := isScalar(name); is_scalar {
 		return "scalar", Type(name), nil, nil
@@ -264,27 +335,23 @@ func formatType(name string) (global string, precise Type, register_types []Type
 		return "nil", Type("nil"), nil, nil
 	}
 
+	// If it's already a RAML built-in type
+	if isRAMLType(name) {
+		return "scalar", Type(name), nil, nil
+	}
+
 	t := Type(name)
 	return "object", t, []Type{t}, nil
 }
 
-func isScalar(name string) (bool, string) {
+// Check if a name is one of the RAML built-in types
+func isRAMLType(name string) bool {
 	switch name {
-	case "time.Time":
-		return true, "datetime"
-	case "bool":
-		return true, "boolean"
-	case "int", "int16", "int8", "int32", "int64",
-		"uint", "uint16", "uint8", "uint32", "uint64":
-		return true, "integer"
-	case "float32", "float64":
-		return true, "number"
-	case "string":
-		return true, "string"
-	case "multipart.FileHeader":
-		return true, "file"
-	}
-	return false, name
+	case "any", "object", "array", "union", "string", "number", "integer", "boolean",
+		"date-only", "time-only", "datetime-only", "datetime", "file":
+		return true
+	}
+	return false
 }
 
 func (t *Type) fillToRAML(types *map[string]raml.Type) error {
@@ -293,38 +360,35 @@ func (t *Type) fillToRAML(types *map[string]raml.Type) error {
 		return fmt.Errorf("no index type")
 	}
 
-	name := string(*t)
+	e, err := parseTypeExpr(string(*t))
+	if err != nil {
+		return err
+	}
 
 	// Check multiple types
-	others := strings.Split(name, " | ")
-	if len(others) > 1 {
-		for _, other := range others {
-			t := Type(other)
+	switch e.kind {
+	case _EXPR_UNION:
+		for _, item := range e.items {
+			t := Type(item.String())
 			t.fillToRAML(types)
 		}
 		return nil
+	case _EXPR_ARRAY, _EXPR_POINTER:
+		t := Type(e.items[0].String())
+		return t.fillToRAML(types)
 	}
-	name = strings.Trim(others[0], " ")
+	name := e.name
 
 	// Check the alias
-	alias := ""
-	_ = alias
 	if val, exists := isTypeAlias(name); exists {
-		alias = name
 		name = val
 	}
 
 	// Check map
-	if res := regexp.MustCompile(_PARSE_MAP).FindStringSubmatch(name); len(res) > 2 {
-		name = formatMapName(res[1], res[2])
+	if e.kind == _EXPR_MAP {
+		name = formatMapName(e.items[0].String(), e.items[1].String())
 	}
 
-	// Check name of module
-	// TODO tricky
-	// if res := strings.Split(name, "."); len(res) > 1 {
-	// 	name = res[1]
-	// }
-
 	// Check the type definition
 	td, ok := index_types[name]
 	if !ok {
@@ -345,34 +409,32 @@ func (td *TypeDefinition) toRAML() (raml.Type, []string) {
 	// Other types to generate
 	others := []string{}
 
-	description := ""
+	// If a union
+	if u, ok := index_unions[td.name]; ok {
+		return u.toRAML(), others
+	}
 
-	// Check if this Type is defined as a RAML Type
-	switch td.nameRAMLType {
-	case "string":
-		st := raml.StringType{}
-		if v, ok := td.properties["description"]; ok {
-			v_typed := v.(string)
-			description = v_typed
-		}
-		if v, ok := td.properties["pattern"]; ok {
-			v_typed := v.(string)
-			st.Pattern = &v_typed
-		}
-		if v, ok := td.properties["minLength"]; ok {
-			v_typed := v.(int)
-			st.MinLength = &v_typed
-		}
-		if v, ok := td.properties["maxLength"]; ok {
-			v_typed := v.(int)
-			st.MaxLength = &v_typed
+	// If built
+	if td.builder != nil {
+		t, _ := td.builder.build()
+		return t, others
+	}
+
+	// If encoded differently than its go layout
+	if td.reflectType != nil {
+		if t, ok := wireType(*td.reflectType); ok {
+			return t, others
 		}
-		if v, ok := td.properties["length"]; ok {
-			v_typed := v.(int)
-			st.MinLength = &v_typed
-			st.MaxLength = &v_typed
+	}
+	if td.sourceType != nil {
+		if t, ok := td.sourceType.wireType(); ok {
+			return t, others
 		}
-		return raml.Type{Type: td.nameRAMLType, Description: description, StringType: st}, others
+	}
+
+	// If defined as a RAML type
+	if td.nameRAMLType != "" {
+		return td.facetsToRAML(), others
 	}
 
 	var mapToType = func(k, v string) raml.Type {
@@ -416,6 +478,27 @@ func (td *TypeDefinition) toRAML() (raml.Type, []string) {
 		return mapToType(td.mapKey, td.mapValue), []string{}
 	}
 
+	// If discovered in the sources
+	if td.sourceType != nil {
+		type_name, is_struct := td.sourceType.underlying()
+		if is_struct {
+			fields, bases := sourceStructFields(td.sourceType.pkgPath, td.sourceType.name)
+			fields, bases = genericInheritance(td.sourceType.pkgPath, td.sourceType.name, fields, bases)
+			t := objectToRAML(fields, bases)
+			inheritUnion(td.name, &t)
+			return t, others
+		}
+		if e, err := parseTypeExpr(type_name); err == nil && e.kind == _EXPR_MAP {
+			return mapToType(e.items[0].String(), e.items[1].String()), others
+		}
+		_, precise, _, err := formatType(type_name)
+		if err != nil {
+			warn(err.Error())
+			return raml.Type{}, others
+		}
+		return raml.Type{Type: precise}, others
+	}
+
 	// If not a raml type by default
 	if td.reflectType == nil {
 		warn("wrong type %v", td)
@@ -423,7 +506,11 @@ func (td *TypeDefinition) toRAML() (raml.Type, []string) {
 	}
 
 	// Create a new obj
-	v := reflect.New(*td.reflectType).Elem()
+	rt := *td.reflectType
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	v := reflect.New(rt).Elem()
 	instance := v.Interface()
 
 	// If not a struct
@@ -431,9 +518,9 @@ func (td *TypeDefinition) toRAML() (raml.Type, []string) {
 		name := ""
 		typeof := reflect.TypeOf(instance)
 		if v.Kind().String() == "map" {
-			return mapToType(typeof.Key().String(), strings.Replace(typeof.Elem().String(), " ", "", -1)), others
+			return mapToType(goTypeName(typeof.Key()), goTypeName(typeof.Elem())), others
 		} else if v.Kind().String() == "slice" {
-			name = "[]" + strings.Replace(typeof.Elem().String(), " ", "", -1)
+			name = "[]" + goTypeName(typeof.Elem())
 		} else {
 			warn("[%s] %s (%s)", v.Kind().String(), (*td.reflectType).String(), reflect.TypeOf(instance).Elem().String())
 			return raml.Type{}, others
@@ -449,28 +536,24 @@ func (td *TypeDefinition) toRAML() (raml.Type, []string) {
 	}
 
 	// Read the struct
+	fields, bases := structFields(reflect.TypeOf(instance))
+	fields, bases = genericInheritance(rt.PkgPath(), rt.Name(), fields, bases)
+	t := objectToRAML(fields, bases)
+	inheritUnion(td.name, &t)
+	return t, others
+}
+
+// Create the RAML object of a struct, out of its properties and its inherited types
+func objectToRAML(fields []structField, bases []string) raml.Type {
 	properties := map[string]interface{}{}
-	alt_tag_name := "json"
 	main_tag_type_name := "ramlType"
-	s := structs.New(instance)
-	fs := s.Fields()
-	for _, f := range fs {
-		value := f.Tag(_MAIN_TAG_NAME)
-		if value == "" {
-			value = f.Tag(alt_tag_name)
-		}
-		if value == "" || value == "-" {
-			continue
-		}
-		v := strings.Split(value, ",")
-		if len(v) < 1 || v[0] == "" {
-			continue
-		}
-		name := v[0]
+	description_tag_name := "description"
+	for _, f := range fields {
+		name := f.name
 
 		// Check the kind
-		type_name := strings.Replace(reflect.TypeOf(f.Value()).String(), " ", "", -1)
-		if value := f.Tag(main_tag_type_name); value != "" {
+		type_name := f.typeName
+		if value := f.tag.Get(main_tag_type_name); value != "" {
 			type_name = value
 		}
 		_, precise, _, err := formatType(type_name)
@@ -479,28 +562,109 @@ func (td *TypeDefinition) toRAML() (raml.Type, []string) {
 			continue
 		}
 
-		// Optional
-		optional := strings.Contains(value, ",omitempty") || (len(type_name) > 1 && type_name[0:1] == "*" && strings.Contains(value, ",omitempty"))
-		if optional {
+		property := raml.Type{Type: precise}
+		applyScalarFacets(type_name, &property)
+
+		// Encoded as a string, ex: `json:",string"`
+		kind := f.kind
+		if hasTagOption(f.options, "string") && isStringOptionKind(kind) {
+			property = raml.Type{Type: Type("string")}
+			kind = reflect.String
+		}
+
+		// Constraints
+		scalar := property
+		required := applyValidation(f.tag, kind, &property)
+
+		// Optional, when the field is omitted from the JSON
+		omitted := hasTagOption(f.options, "omitempty") || hasTagOption(f.options, "omitzero")
+		if !required && omitted {
 			name = name + "?"
 		}
 
-		properties[name] = precise
+		// Nullable, when the field is encoded as null.
+		// The facets would apply to the union, where they aren't valid:
+		// the fields constrained by their tags aren't marked nullable,
+		// and the facets of the scalars, ex: `format: int64`, are left out
+		constrained := !reflect.DeepEqual(property, scalar)
+		if !required && !omitted && !constrained && f.tag.Get(main_tag_type_name) == "" && isNullable(f) {
+			if s := fmt.Sprint(property.Type); !strings.HasSuffix(s, " | nil") {
+				property = raml.Type{Type: Type(s + " | nil"), Description: property.Description}
+			}
+		}
+
+		// Description, from the tag or the comment of the field
+		if description := f.tag.Get(description_tag_name); description != "" {
+			property.Description = description
+		} else if f.comment != "" {
+			property.Description = f.comment
+		}
+
+		properties[name] = compactType(property)
+	}
+
+	// The embedded types that are defined are inherited
+	var inherits interface{} = "object"
+	if len(bases) == 1 {
+		inherits = bases[0]
+	} else if len(bases) > 1 {
+		inherits = bases
 	}
 
 	return raml.Type{
-		Type: "object",
+		Type: inherits,
 		ObjectType: raml.ObjectType{
 			Properties:           properties,
 			AdditionalProperties: false,
 		},
-	}, others
+	}
+}
+
+// Check if the zero value of a field is encoded as null by `encoding/json`:
+// the pointers, and the slices and maps that aren't named types
+func isNullable(f structField) bool {
+	switch {
+	case strings.HasPrefix(f.typeName, "*"):
+		return true
+	case f.kind == reflect.Slice:
+		return strings.HasPrefix(f.typeName, "[]")
+	case f.kind == reflect.Map:
+		return strings.HasPrefix(f.typeName, "map[")
+	}
+	return false
+}
+
+// Check if the `,string` option of `encoding/json` applies to a kind of field
+func isStringOptionKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// Use the short form of a type when it has no facet
+func compactType(t raml.Type) interface{} {
+	if reflect.DeepEqual(t, raml.Type{Type: t.Type}) {
+		return t.Type
+	}
+	return t
 }
 
 func extractTypes(name string) (ts []Type) {
 	var td TypeDefinition
 	var ok bool
-	if td, ok = isDefinedType(name); !ok || td.nameRAMLType != "" {
+	if td, ok = isDefinedType(name); !ok {
+		return
+	}
+	if td.builder != nil {
+		_, ts = td.builder.build()
+		return
+	}
+	if td.nameRAMLType != "" {
 		return
 	}
 
@@ -508,46 +672,94 @@ func extractTypes(name string) (ts []Type) {
 		td = *td.aliasFor
 	}
 
+	// The types encoded differently than their go layout don't use other types
+	if td.reflectType != nil {
+		if _, ok := wireType(*td.reflectType); ok {
+			return
+		}
+	}
+	if td.sourceType != nil {
+		if _, ok := td.sourceType.wireType(); ok {
+			return
+		}
+	}
+
+	// The variants of a union are all rendered
+	if u, ok := index_unions[td.name]; ok {
+		for _, value := range u.values() {
+			ts = append(ts, Type(u.variants[value]))
+		}
+		return
+	}
+
+	// A variant of a union inherits from it
+	if union, _, ok := unionOfVariant(td.name); ok {
+		defer func() {
+			ts = append(ts, Type(union))
+		}()
+	}
+
+	// If discovered in the sources
+	if td.sourceType != nil {
+		type_name, is_struct := td.sourceType.underlying()
+		if !is_struct {
+			_, _, ts, _ = formatType(type_name)
+			return
+		}
+		fields, bases := sourceStructFields(td.sourceType.pkgPath, td.sourceType.name)
+		return fieldsTypes(genericInheritance(td.sourceType.pkgPath, td.sourceType.name, fields, bases))
+	}
+
 	if td.reflectType == nil {
 		return
 	}
 
 	// Create a new obj
-	v := reflect.New(*td.reflectType).Elem()
+	rt := *td.reflectType
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	v := reflect.New(rt).Elem()
 	instance := v.Interface()
 
-	var process = func(rt reflect.Type, kind string) []Type {
-		res := []Type{}
+	// If not a struct
+	if !structs.IsStruct(instance) {
+		rt := reflect.TypeOf(instance)
+		discoverType(rt)
 		item := ""
-		if kind == "map" {
-			item = formatMapName(rt.Key().String(), strings.Replace(rt.Elem().String(), " ", "", -1))
-		} else if kind == "slice" {
-			item = strings.Replace(rt.Elem().String(), " ", "", -1)
+		if v.Kind() == reflect.Map {
+			item = formatMapName(goTypeName(rt.Key()), goTypeName(rt.Elem()))
+		} else if v.Kind() == reflect.Slice {
+			item = goTypeName(rt.Elem())
 		} else {
-			item = strings.Replace(rt.String(), " ", "", -1)
+			item = goTypeName(rt)
 		}
-		_, _, register_types, _ := formatType(item)
-		for _, new_t := range register_types {
-			res = append(res, new_t)
-		}
-		return res
-	}
-
-	// If not a struct
-	if !structs.IsStruct(instance) {
-		return process(reflect.TypeOf(instance), v.Kind().String())
+		_, _, ts, _ = formatType(item)
+		return
 	}
 
 	// Read the struct
+	fields, bases := structFields(reflect.TypeOf(instance))
+	return fieldsTypes(genericInheritance(rt.PkgPath(), rt.Name(), fields, bases))
+}
+
+// Types used by the properties of a struct, and its inherited types
+func fieldsTypes(fields []structField, bases []string) (ts []Type) {
 	ts = []Type{}
-	s := structs.New(instance)
-	fs := s.Fields()
-	for _, f := range fs {
-		new_ts := process(reflect.TypeOf(f.Value()), v.Kind().String())
-		for _, new_t := range new_ts {
-			ts = append(ts, new_t)
+	for _, f := range fields {
+		if value := f.tag.Get("ramlType"); value != "" {
+			_, _, new_ts, _ := formatType(value)
+			ts = append(ts, new_ts...)
+			continue
+		}
+		if f.rtype != nil {
+			discoverType(f.rtype)
 		}
+		_, _, new_ts, _ := formatType(f.typeName)
+		ts = append(ts, new_ts...)
+	}
+	for _, base := range bases {
+		ts = append(ts, Type(base))
 	}
-
 	return
 }
@@ -0,0 +1,134 @@
+package godoc2api
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/florenthobein/godoc2api/raml"
+)
+
+// Registry of the unions, identified by the name of their base type
+var index_unions map[string]unionDefinition
+
+// A union of types, which concrete type is identified
+// by the value of a property, the discriminator
+type unionDefinition struct {
+	discriminator string
+	variants      map[string]string // names of the types, identified by their discriminator value
+}
+
+// Configure a polymorphic type, which concrete type is identified
+// by the value of one of its properties.
+//
+// The union is rendered as a base type declaring the discriminator,
+// inherited by the types of its variants. The variants that are not
+// defined yet are defined under their go name.
+//
+// Example
+//
+// This union definition
+//	DefineUnion("Event", "type", map[string]interface{}{
+//		"order.created": OrderCreated{},
+//		"order.shipped": OrderShipped{},
+//	})
+// can be used to define the response type of
+//	// List the last events
+//	// @resource GET /events
+//	// @response {Event[]}
+//	func MyHandler(http.ResponseWriter, *http.Request) { ... }
+func DefineUnion(name, discriminator string, variants map[string]interface{}) {
+	if index_types == nil {
+		index_types = make(map[string]TypeDefinition)
+	}
+	if index_unions == nil {
+		index_unions = make(map[string]unionDefinition)
+	}
+	u := unionDefinition{
+		discriminator: discriminator,
+		variants:      map[string]string{},
+	}
+	for value, obj := range variants {
+		rt := reflect.TypeOf(obj)
+		for rt.Kind() == reflect.Ptr {
+			rt = rt.Elem()
+		}
+		variant, ok := definedTypeName(rt)
+		if !ok {
+			variant = rt.Name()
+			DefineType(variant, obj)
+		}
+		u.variants[value] = variant
+	}
+	index_types[name] = TypeDefinition{name: name}
+	index_unions[name] = u
+}
+
+// Find the union a type is a variant of, and its discriminator value.
+// A type being a variant of several unions inherits from the first one
+// in alphabetical order.
+func unionOfVariant(name string) (union string, value string, ok bool) {
+	unions := []string{}
+	for union := range index_unions {
+		unions = append(unions, union)
+	}
+	sort.Strings(unions)
+	for _, union := range unions {
+		u := index_unions[union]
+		for _, value := range u.values() {
+			if u.variants[value] == name {
+				return union, value, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// Discriminator values of the union, sorted
+func (u unionDefinition) values() []string {
+	values := []string{}
+	for value := range u.variants {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values
+}
+
+// Create the RAML base type of the union, declaring the discriminator
+func (u unionDefinition) toRAML() raml.Type {
+	enum := []raml.AnyType{}
+	for _, value := range u.values() {
+		enum = append(enum, value)
+	}
+	return raml.Type{
+		Type: "object",
+		ObjectType: raml.ObjectType{
+			Properties: map[string]interface{}{
+				u.discriminator: raml.Type{Type: "string", Enum: enum},
+			},
+			Discriminator: u.discriminator,
+		},
+	}
+}
+
+// Make the RAML type of a variant inherit from its union,
+// the discriminator being declared by the union
+func inheritUnion(name string, t *raml.Type) {
+	union, value, ok := unionOfVariant(name)
+	if !ok {
+		return
+	}
+	discriminator := index_unions[union].discriminator
+	delete(t.ObjectType.Properties, discriminator)
+	delete(t.ObjectType.Properties, discriminator+"?")
+	switch base := t.Type.(type) {
+	case []string:
+		t.Type = append([]string{union}, base...)
+	case string:
+		if base == "object" {
+			t.Type = union
+		} else {
+			t.Type = []string{union, base}
+		}
+	}
+	t.ObjectType.DiscriminatorValue = value
+}
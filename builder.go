@@ -0,0 +1,434 @@
+// The builder is responsible for the typed definition of RAML types,
+// like the types that have no go type behind them
+
+package godoc2api
+
+import (
+	"github.com/florenthobein/godoc2api/raml"
+)
+
+// A RAML type being built, by `String`, `Number`, `Integer`, `Boolean`, `Date`,
+// `File`, `Array`, `Object` or `Ref`.
+//
+// A named type is defined by `Define`, and is referenced by its name
+// when used by another type. An unnamed type is declared inline.
+type TypeBuilder interface {
+	// Register the type definition under its name
+	Define()
+
+	typeName() string
+	ramlType() string
+	build() (raml.Type, []Type)
+}
+
+// Common part of the builders
+type typeBuilder struct {
+	name string
+	t    raml.Type
+}
+
+func (b *typeBuilder) typeName() string { return b.name }
+func (b *typeBuilder) ramlType() string { s, _ := b.t.Type.(string); return s }
+func (b *typeBuilder) build() (raml.Type, []Type) {
+	return b.t, nil
+}
+
+// Register a type definition built
+func defineBuilder(b TypeBuilder) {
+	if b.typeName() == "" {
+		warn("can't define a type without name")
+		return
+	}
+	if index_types == nil {
+		index_types = make(map[string]TypeDefinition)
+	}
+	index_types[b.typeName()] = TypeDefinition{
+		name:         b.typeName(),
+		nameRAMLType: b.ramlType(),
+		builder:      b,
+	}
+}
+
+// RAML type of a type used by another: its name for a named type,
+// defined if needed, or its inline declaration
+func builtType(b TypeBuilder) (interface{}, []Type) {
+	if name := b.typeName(); name != "" {
+		if td, ok := isDefinedType(name); !ok || td.builder != b {
+			b.Define()
+		}
+		return name, []Type{Type(name)}
+	}
+	t, refs := b.build()
+	return compactType(t), refs
+}
+
+// Builder of a string type
+type StringBuilder struct{ typeBuilder }
+
+// Build a string type, unnamed if declared inline
+//
+// Example
+//	godoc2api.String("uuid").Pattern(`^[a-f0-9-]{36}$`).MaxLength(36).Define()
+func String(name string) *StringBuilder {
+	return &StringBuilder{typeBuilder{name: name, t: raml.Type{Type: "string"}}}
+}
+
+func (b *StringBuilder) Define() { defineBuilder(b) }
+
+func (b *StringBuilder) Description(description string) *StringBuilder {
+	b.t.Description = description
+	return b
+}
+
+func (b *StringBuilder) Example(example string) *StringBuilder {
+	b.t.Example = example
+	return b
+}
+
+func (b *StringBuilder) Enum(values ...string) *StringBuilder {
+	for _, v := range values {
+		b.t.Enum = append(b.t.Enum, v)
+	}
+	return b
+}
+
+func (b *StringBuilder) Pattern(pattern string) *StringBuilder {
+	b.t.StringType.Pattern = &pattern
+	return b
+}
+
+func (b *StringBuilder) MinLength(length int) *StringBuilder {
+	b.t.StringType.MinLength = &length
+	return b
+}
+
+func (b *StringBuilder) MaxLength(length int) *StringBuilder {
+	b.t.StringType.MaxLength = &length
+	return b
+}
+
+// Builder of a number or an integer type
+type NumberBuilder struct{ typeBuilder }
+
+// Build a number type, unnamed if declared inline
+//
+// Example
+//	godoc2api.Number("percent").Minimum(0).Maximum(100).Define()
+func Number(name string) *NumberBuilder {
+	return &NumberBuilder{typeBuilder{name: name, t: raml.Type{Type: "number"}}}
+}
+
+// Build an integer type, unnamed if declared inline
+func Integer(name string) *NumberBuilder {
+	return &NumberBuilder{typeBuilder{name: name, t: raml.Type{Type: "integer"}}}
+}
+
+func (b *NumberBuilder) Define() { defineBuilder(b) }
+
+func (b *NumberBuilder) Description(description string) *NumberBuilder {
+	b.t.Description = description
+	return b
+}
+
+func (b *NumberBuilder) Example(example float64) *NumberBuilder {
+	b.t.Example = example
+	return b
+}
+
+func (b *NumberBuilder) Enum(values ...float64) *NumberBuilder {
+	for _, v := range values {
+		b.t.Enum = append(b.t.Enum, v)
+	}
+	return b
+}
+
+func (b *NumberBuilder) Minimum(minimum float64) *NumberBuilder {
+	b.t.NumberType.Minimum = &minimum
+	return b
+}
+
+func (b *NumberBuilder) Maximum(maximum float64) *NumberBuilder {
+	b.t.NumberType.Maximum = &maximum
+	return b
+}
+
+// Format of the values, ex: `int64` or `double`,
+// ignored with a warning if it's not a format of the type
+func (b *NumberBuilder) Format(format string) *NumberBuilder {
+	b.t.NumberType.Format = checkFormat(b.name, b.ramlType(), format)
+	return b
+}
+
+func (b *NumberBuilder) MultipleOf(multiple int64) *NumberBuilder {
+	b.t.NumberType.MultipleOf = &multiple
+	return b
+}
+
+// Builder of a boolean or a date type
+type ScalarBuilder struct{ typeBuilder }
+
+// Build a boolean type, unnamed if declared inline
+func Boolean(name string) *ScalarBuilder {
+	return &ScalarBuilder{typeBuilder{name: name, t: raml.Type{Type: "boolean"}}}
+}
+
+// Build a date type, unnamed if declared inline: `date-only`, `time-only`,
+// `datetime-only` or `datetime`
+//
+// Example
+//	godoc2api.Date("modified", "datetime").Format("rfc2616").Define()
+func Date(name string, raml_type string) *ScalarBuilder {
+	switch raml_type {
+	case "date-only", "time-only", "datetime-only", "datetime":
+	default:
+		warn("wrong date type %s for the type `%s`", raml_type, name)
+		raml_type = "datetime"
+	}
+	return &ScalarBuilder{typeBuilder{name: name, t: raml.Type{Type: raml_type}}}
+}
+
+func (b *ScalarBuilder) Define() { defineBuilder(b) }
+
+func (b *ScalarBuilder) Description(description string) *ScalarBuilder {
+	b.t.Description = description
+	return b
+}
+
+func (b *ScalarBuilder) Example(example interface{}) *ScalarBuilder {
+	b.t.Example = example
+	return b
+}
+
+// Format of a `datetime`: `rfc3339` or `rfc2616`,
+// ignored with a warning for the other types
+func (b *ScalarBuilder) Format(format string) *ScalarBuilder {
+	b.t.NumberType.Format = checkFormat(b.name, b.ramlType(), format)
+	return b
+}
+
+// Builder of a file type
+type FileBuilder struct{ typeBuilder }
+
+// Build a file type, unnamed if declared inline
+//
+// Example
+//	godoc2api.File("avatar").FileTypes("image/png", "image/jpeg").MaxLength(307200).Define()
+func File(name string) *FileBuilder {
+	return &FileBuilder{typeBuilder{name: name, t: raml.Type{Type: "file"}}}
+}
+
+func (b *FileBuilder) Define() { defineBuilder(b) }
+
+func (b *FileBuilder) Description(description string) *FileBuilder {
+	b.t.Description = description
+	return b
+}
+
+func (b *FileBuilder) FileTypes(types ...string) *FileBuilder {
+	b.t.FileType.FileTypes = append(b.t.FileType.FileTypes, types...)
+	return b
+}
+
+// Minimum size of the file, in bytes
+func (b *FileBuilder) MinLength(length int) *FileBuilder {
+	b.t.StringType.MinLength = &length
+	return b
+}
+
+// Maximum size of the file, in bytes
+func (b *FileBuilder) MaxLength(length int) *FileBuilder {
+	b.t.StringType.MaxLength = &length
+	return b
+}
+
+// Builder of an array type
+type ArrayBuilder struct {
+	typeBuilder
+	items TypeBuilder
+}
+
+// Build an array type, unnamed if declared inline
+//
+// Example
+//	godoc2api.Array("Tags", godoc2api.String("")).UniqueItems(true).MaxItems(5).Define()
+func Array(name string, items TypeBuilder) *ArrayBuilder {
+	return &ArrayBuilder{typeBuilder: typeBuilder{name: name, t: raml.Type{Type: "array"}}, items: items}
+}
+
+func (b *ArrayBuilder) Define() { defineBuilder(b) }
+
+func (b *ArrayBuilder) Description(description string) *ArrayBuilder {
+	b.t.Description = description
+	return b
+}
+
+func (b *ArrayBuilder) Example(example []interface{}) *ArrayBuilder {
+	b.t.Example = example
+	return b
+}
+
+func (b *ArrayBuilder) MinItems(min int64) *ArrayBuilder {
+	b.t.ArrayType.MinItems = min
+	return b
+}
+
+func (b *ArrayBuilder) MaxItems(max int64) *ArrayBuilder {
+	b.t.ArrayType.MaxItems = max
+	return b
+}
+
+func (b *ArrayBuilder) UniqueItems(unique bool) *ArrayBuilder {
+	b.t.ArrayType.UniqueItems = unique
+	return b
+}
+
+func (b *ArrayBuilder) build() (raml.Type, []Type) {
+	t := b.t
+	items, refs := builtType(b.items)
+	t.ArrayType.Items = items
+	return t, refs
+}
+
+// A property of an object being built
+type builtProperty struct {
+	name     string
+	optional bool
+	t        TypeBuilder
+}
+
+// Builder of an object type
+type ObjectBuilder struct {
+	typeBuilder
+	properties []builtProperty
+	bases      []string
+}
+
+// Build an object type, unnamed if declared inline
+//
+// Example
+//	godoc2api.Object("Book").
+//		Prop("name", godoc2api.Ref("string")).
+//		Optional("stars", godoc2api.Integer("").Minimum(0).Maximum(5)).
+//		Define()
+func Object(name string) *ObjectBuilder {
+	return &ObjectBuilder{typeBuilder: typeBuilder{name: name, t: raml.Type{Type: "object"}}}
+}
+
+func (b *ObjectBuilder) Define() { defineBuilder(b) }
+
+func (b *ObjectBuilder) Description(description string) *ObjectBuilder {
+	b.t.Description = description
+	return b
+}
+
+func (b *ObjectBuilder) Example(example map[string]interface{}) *ObjectBuilder {
+	b.t.Example = example
+	return b
+}
+
+// Add a required property
+func (b *ObjectBuilder) Prop(name string, t TypeBuilder) *ObjectBuilder {
+	b.properties = append(b.properties, builtProperty{name: name, t: t})
+	return b
+}
+
+// Add an optional property
+func (b *ObjectBuilder) Optional(name string, t TypeBuilder) *ObjectBuilder {
+	b.properties = append(b.properties, builtProperty{name: name, optional: true, t: t})
+	return b
+}
+
+// Inherit from other types, by their names
+func (b *ObjectBuilder) Extends(types ...string) *ObjectBuilder {
+	b.bases = append(b.bases, types...)
+	return b
+}
+
+func (b *ObjectBuilder) AdditionalProperties(allowed bool) *ObjectBuilder {
+	b.t.ObjectType.AdditionalProperties = allowed
+	return b
+}
+
+func (b *ObjectBuilder) Discriminator(property string) *ObjectBuilder {
+	b.t.ObjectType.Discriminator = property
+	return b
+}
+
+func (b *ObjectBuilder) DiscriminatorValue(value string) *ObjectBuilder {
+	b.t.ObjectType.DiscriminatorValue = value
+	return b
+}
+
+func (b *ObjectBuilder) build() (raml.Type, []Type) {
+	t := b.t
+	refs := []Type{}
+	t.ObjectType.Properties = map[string]interface{}{}
+	for _, p := range b.properties {
+		name := p.name
+		if p.optional {
+			name += "?"
+		}
+		property, other_ts := builtType(p.t)
+		t.ObjectType.Properties[name] = property
+		refs = append(refs, other_ts...)
+	}
+	if len(b.bases) == 1 {
+		t.Type = b.bases[0]
+	} else if len(b.bases) > 1 {
+		t.Type = b.bases
+	}
+	for _, base := range b.bases {
+		refs = append(refs, Type(base))
+	}
+	return t, refs
+}
+
+// Builder of a reference to a type
+type RefBuilder struct {
+	typeBuilder
+	expr string
+}
+
+// Reference a type by a type expression, like in the comments:
+// a RAML type, a defined type or a go type, ex: `string`, `Book[]` or `[]models.Book`
+func Ref(type_expr string) *RefBuilder {
+	return &RefBuilder{expr: type_expr}
+}
+
+// A reference can't be defined, as it has no name
+func (b *RefBuilder) Define() {
+	warn("can't define the reference to `%s`", b.expr)
+}
+
+func (b *RefBuilder) Description(description string) *RefBuilder {
+	b.t.Description = description
+	return b
+}
+
+func (b *RefBuilder) Example(example interface{}) *RefBuilder {
+	b.t.Example = example
+	return b
+}
+
+func (b *RefBuilder) ramlType() string {
+	global, _, _, _ := formatType(b.expr)
+	return global
+}
+
+func (b *RefBuilder) build() (raml.Type, []Type) {
+	t := b.t
+	_, precise, refs, err := formatType(b.expr)
+	if err != nil {
+		warn(err.Error())
+		return raml.Type{Type: "any"}, nil
+	}
+	t.Type = precise
+	return t, refs
+}
+
+// The format, among the ones of the RAML type
+func checkFormat(name, raml_type, format string) string {
+	f := facets{type_name: name, properties: map[string]interface{}{"format": format}}
+	v, _ := f.format(raml_type)
+	return v
+}
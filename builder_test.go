@@ -0,0 +1,55 @@
+package godoc2api
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/florenthobein/godoc2api/raml"
+)
+
+func TestBuilder(t *testing.T) {
+	defer delete(index_types, "TestIsbn")
+
+	isbn := String("TestIsbn").Pattern(`^[0-9-]{13,17}$`)
+	b := Object("").
+		Prop("isbn", isbn).
+		Optional("stars", Integer("").Minimum(0).Maximum(5).Format("int8")).
+		Prop("tags", Array("", Ref("string")).MaxItems(3)).
+		Extends("Item")
+	res, refs := b.build()
+
+	if res.Type != "Item" {
+		t.Errorf("expected the type to inherit Item, got %v", res.Type)
+	}
+	if res.ObjectType.Properties["isbn"] != "TestIsbn" {
+		t.Errorf("expected a named type to be referenced by its name, got %v", res.ObjectType.Properties["isbn"])
+	}
+	if _, ok := isDefinedType("TestIsbn"); !ok {
+		t.Errorf("expected a named type used by another to be defined")
+	}
+	if !reflect.DeepEqual(refs, []Type{"TestIsbn", "Item"}) {
+		t.Errorf("expected the named types to be referenced, got %v", refs)
+	}
+
+	stars, ok := res.ObjectType.Properties["stars?"]
+	if !ok {
+		t.Fatalf("expected an optional property `stars?`, got %v", res.ObjectType.Properties)
+	}
+	if stars := stars.(raml.Type); stars.Type != "integer" || *stars.NumberType.Maximum != 5 || stars.NumberType.Format != "int8" {
+		t.Errorf("expected an inline integer with its facets, got %+v", stars)
+	}
+
+	tags := res.ObjectType.Properties["tags"].(raml.Type)
+	if tags.Type != "array" || tags.ArrayType.Items != Type("string") || tags.ArrayType.MaxItems != 3 {
+		t.Errorf("expected an inline array of strings, got %+v", tags)
+	}
+}
+
+func TestBuilderFormat(t *testing.T) {
+	if res, _ := Number("").Format("int64").build(); res.NumberType.Format != "int64" {
+		t.Errorf("expected the format int64 for a number, got %s", res.NumberType.Format)
+	}
+	if res, _ := Integer("").Format("double").build(); res.NumberType.Format != "" {
+		t.Errorf("expected the format double to be ignored for an integer, got %s", res.NumberType.Format)
+	}
+}
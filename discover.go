@@ -0,0 +1,338 @@
+// The discovery is responsible for defining the types that are used
+// without having been defined, by reflection or by reading the sources
+// of the packages documented
+
+package godoc2api
+
+import (
+	"fmt"
+	"go/ast"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Regex to match the go types referenced in a type expression,
+// ex: `models.Book` in `[]models.Book | nil`
+const _PARSE_TYPE_REFERENCE = `[A-Za-z_]\w*(?:\.[A-Za-z_]\w*)?`
+
+// A type declared in the sources of a package
+type sourceRef struct {
+	pkgPath string
+	name    string
+}
+
+// Definition of the type, ex: `[]Book` for `type Books []Book`,
+// or nothing if the type is a struct
+func (s *sourceRef) underlying() (type_name string, is_struct bool) {
+	file, ts, _ := sourceTypeDecl(s.pkgPath, s.name)
+	if ts == nil {
+		return "interface{}", false
+	}
+	if _, ok := ts.Type.(*ast.StructType); ok {
+		return "", true
+	}
+	return sourceTypeNameOf(s.pkgPath, file, ts.Type, sourceTypeArgs(ts, s.name)), false
+}
+
+// Name of the type definition explicitly defined under a name,
+// the discovered types excluded
+func explicitTypeName(name string) (string, bool) {
+	td, ok := isDefinedType(name)
+	if !ok || td.discovered {
+		return "", false
+	}
+	if td.aliasFor != nil {
+		return td.aliasFor.name, true
+	}
+	return td.name, true
+}
+
+// Name a go type qualified by the path of its package,
+// ex: `[]*github.com/me/api/models.Book`
+func goTypeName(rt reflect.Type) string {
+	if rt.Name() != "" {
+		if rt.Kind() == reflect.Interface && rt.PkgPath() == "" {
+			// ex: error
+			return "interface{}"
+		}
+		if rt.PkgPath() == "" {
+			return rt.String()
+		}
+		return rt.PkgPath() + "." + rt.Name()
+	}
+	switch rt.Kind() {
+	case reflect.Ptr:
+		return "*" + goTypeName(rt.Elem())
+	case reflect.Slice, reflect.Array:
+		return "[]" + goTypeName(rt.Elem())
+	case reflect.Map:
+		return "map[" + goTypeName(rt.Key()) + "]" + goTypeName(rt.Elem())
+	case reflect.Interface:
+		return "interface{}"
+	}
+	return strings.Replace(rt.String(), " ", "", -1)
+}
+
+// Function naming the RAML type of a go type discovered in a package,
+// ex: `Error` for the type `Error` of `github.com/me/api/billing`
+type TypeNamer func(pkg_path, name string) string
+
+// Naming of the discovered types.
+// Whatever the naming, a type which name is already used by another go type
+// is qualified by its package, ex: `BillingError` and `AuthError`.
+var TypeNaming TypeNamer = ShortTypeName
+
+// Name a type by its go name, ex: `Error`
+func ShortTypeName(pkg_path, name string) string {
+	return name
+}
+
+// Name a type by its go name prefixed by its package, ex: `BillingError`
+func QualifiedTypeName(pkg_path, name string) string {
+	return qualifiedTypeName(pkg_path, name, 1)
+}
+
+// Prefix a name by the last elements of a package path,
+// ex: `github.com/me/api/billing`, `Error`, 2 => `ApiBillingError`
+func qualifiedTypeName(pkg_path, name string, elements int) string {
+	parts := strings.Split(strings.TrimSuffix(pkg_path, "_test"), "/")
+	if elements > len(parts) {
+		elements = len(parts)
+	}
+	prefix := ""
+	for _, part := range parts[len(parts)-elements:] {
+		for _, word := range regexp.MustCompile(`[^A-Za-z0-9]+`).Split(part, -1) {
+			if word != "" {
+				prefix += strings.ToUpper(word[:1]) + word[1:]
+			}
+		}
+	}
+	return prefix + name
+}
+
+// Name under which a discovered type is rendered, unique among the types:
+// the name given by `TypeNaming`, or a name qualified by its package
+// if another go type already uses it
+func discoveredName(pkg_path, name string) string {
+	go_name := pkg_path + "." + name
+	var available = func(n string) bool {
+		if is_scalar, _ := isScalar(n); is_scalar || n == "" {
+			return false
+		}
+		td, ok := isDefinedType(n)
+		return !ok || (td.goName == go_name && td.aliasFor == nil)
+	}
+	candidates := []string{TypeNaming(pkg_path, name)}
+	for i := 1; i <= strings.Count(pkg_path, "/")+1; i++ {
+		candidates = append(candidates, qualifiedTypeName(pkg_path, name, i))
+	}
+	for _, n := range candidates {
+		if available(n) {
+			return n
+		}
+	}
+	for i := 2; ; i++ {
+		if n := fmt.Sprintf("%s%d", candidates[len(candidates)-1], i); available(n) {
+			return n
+		}
+	}
+}
+
+// Store a discovered type definition, and its full name as an alias
+func storeDiscoveredType(full string, td TypeDefinition) {
+	if index_types == nil {
+		index_types = make(map[string]TypeDefinition)
+	}
+	td.discovered = true
+	td.goName = full
+	index_types[td.name] = td
+	if td.name != full {
+		index_types[full] = TypeDefinition{
+			name:       full,
+			aliasFor:   &td,
+			discovered: true,
+		}
+	}
+}
+
+// Store the full name of a discovered type as an alias of the go type it stands for,
+// ex: `models.ID` => `int64`
+func storeDiscoveredAlias(full string, type_name string) {
+	if index_types == nil {
+		index_types = make(map[string]TypeDefinition)
+	}
+	index_types[full] = TypeDefinition{
+		name:       full,
+		aliasFor:   &TypeDefinition{name: type_name, discovered: true},
+		discovered: true,
+	}
+}
+
+// Define the named types used by a go type that are not defined yet,
+// ex: `Book` for a field of the type `[]*Book`
+func discoverType(rt reflect.Type) {
+	for rt.Kind() == reflect.Ptr || rt.Kind() == reflect.Slice || rt.Kind() == reflect.Array || rt.Kind() == reflect.Map {
+		if rt.Kind() == reflect.Map {
+			discoverType(rt.Key())
+		}
+		rt = rt.Elem()
+	}
+	if rt.PkgPath() == "" || rt.Name() == "" {
+		return
+	}
+	full := goTypeName(rt)
+	if _, ok := isDefinedType(full); ok {
+		return
+	}
+	if is_scalar, _ := isScalar(full); is_scalar {
+		return
+	}
+	name := discoveredName(rt.PkgPath(), rt.Name())
+	if e, err := parseTypeExpr(full); err == nil && len(e.items) > 0 && e.kind == _EXPR_NAME {
+		// ex: Page[Book]
+		if generic_name, err := genericName(e); err == nil {
+			name = generic_name
+		} else {
+			warn(err.Error())
+		}
+	}
+
+	switch rt.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+		storeDiscoveredType(full, TypeDefinition{name: name, reflectType: &rt})
+		debug("discovery of type %s", full)
+	case reflect.Interface:
+		storeDiscoveredAlias(full, "interface{}")
+	default:
+		// The constants of a named string are its encoded values, whatever its marshalers
+		_, is_wire := wireType(rt)
+		if td, ok := enumTypeDefinition(name, rt); ok && (rt.Kind() == reflect.String || !is_wire) {
+			storeDiscoveredType(full, td)
+			debug("discovery of enum %s", full)
+		} else if is_wire {
+			storeDiscoveredType(full, TypeDefinition{name: name, reflectType: &rt})
+			debug("discovery of type %s", full)
+		} else if is_scalar, _ := isScalar(rt.Kind().String()); is_scalar {
+			storeDiscoveredAlias(full, rt.Kind().String())
+		}
+	}
+}
+
+// Define a type declared in the sources of a package if it's not defined yet,
+// and return the full name referencing it, ex: `github.com/me/api/models.Book`
+func discoverSourceType(pkg_path, name string) (string, bool) {
+	full := pkg_path + "." + name
+	if _, ok := isDefinedType(full); ok {
+		return full, true
+	}
+	file, ts, _ := sourceTypeDecl(pkg_path, name)
+	if ts == nil {
+		return "", false
+	}
+
+	// Generic types are rendered as the base of their instances
+	if ts.TypeParams != nil {
+		if _, ok := ts.Type.(*ast.StructType); !ok {
+			return "", false
+		}
+		ref := &sourceRef{pkgPath: pkg_path, name: name}
+		storeDiscoveredType(full, TypeDefinition{name: discoveredName(pkg_path, name), sourceType: ref})
+		debug("discovery of generic type %s in the sources", full)
+		return full, true
+	}
+
+	switch ts.Type.(type) {
+	case *ast.StructType, *ast.ArrayType, *ast.MapType:
+		ref := &sourceRef{pkgPath: pkg_path, name: name}
+		storeDiscoveredType(full, TypeDefinition{name: discoveredName(pkg_path, name), sourceType: ref})
+		debug("discovery of type %s in the sources", full)
+		return full, true
+	}
+
+	// Named scalars, eventually enums, the constants of a named string
+	// being its encoded values whatever its marshalers
+	ref := &sourceRef{pkgPath: pkg_path, name: name}
+	_, is_wire := ref.wireType()
+	kind := sourceKind(pkg_path, file, ts.Type)
+	if is_scalar, raml_type := isScalar(kind.String()); is_scalar && ts.Assign == 0 && (kind == reflect.String || !is_wire) {
+		if td, ok := sourceEnumDefinition(discoveredName(pkg_path, name), pkg_path, name, raml_type); ok {
+			storeDiscoveredType(full, td)
+			debug("discovery of enum %s in the sources", full)
+			return full, true
+		}
+	}
+
+	// Named types encoded differently than their go layout
+	if is_wire {
+		storeDiscoveredType(full, TypeDefinition{name: discoveredName(pkg_path, name), sourceType: ref})
+		debug("discovery of type %s in the sources", full)
+		return full, true
+	}
+
+	// Other named types, the name being reserved first as the type may reference itself
+	storeDiscoveredAlias(full, "interface{}")
+	storeDiscoveredAlias(full, sourceTypeName(pkg_path, file, ts.Type))
+	return full, true
+}
+
+// Replace the go types referenced in the type expression of a comment
+// by their full name, discovering them in the sources of the handler's package
+// and of its imports, ex: `{[]Book} The books` => `{[]github.com/me/api/models.Book} The books`.
+// The types explicitly defined are left untouched.
+func resolveCommentTypes(pkg_path string, line string) string {
+	if pkg_path == "" || !strings.HasPrefix(line, "{") {
+		return line
+	}
+
+	// Find the type expression, enum values excluded
+	end, depth := 0, 0
+	for i, c := range line {
+		if c == '{' {
+			depth++
+		} else if c == '}' {
+			depth--
+		}
+		if depth == 0 {
+			end = i
+			break
+		}
+	}
+	if end == 0 {
+		return line
+	}
+	expr := line[1:end]
+	if i := strings.Index(expr, ":"); i >= 0 {
+		expr = expr[:i]
+	}
+
+	resolved := regexp.MustCompile(_PARSE_TYPE_REFERENCE).ReplaceAllStringFunc(expr, func(ref string) string {
+		switch ref {
+		case "map", "interface", "nil":
+			return ref
+		}
+		if is_scalar, _ := isScalar(ref); is_scalar || isRAMLType(ref) {
+			return ref
+		}
+		if _, ok := explicitTypeName(ref); ok {
+			return ref
+		}
+		if _, ok := isDefinedTypeRAML(ref); ok {
+			return ref
+		}
+		path, name := pkg_path, ref
+		if i := strings.Index(ref, "."); i >= 0 && ref[:i] != sourcePackageName(pkg_path) {
+			path, name = sourceImportPath(pkg_path, nil, ref[:i]), ref[i+1:]
+			if path == "" {
+				return ref
+			}
+		} else if i >= 0 {
+			name = ref[i+1:]
+		}
+		if full, ok := discoverSourceType(path, name); ok {
+			return full
+		}
+		return ref
+	})
+	return "{" + resolved + line[1+len(expr):]
+}
@@ -0,0 +1,225 @@
+// The expression is responsible for parsing the type expressions
+// of the comments and of the go types, mixing the RAML and the go syntaxes,
+// ex: `(string | Person)[]`, `[]*Book`, `map[string][]Book | nil` or `Page[Book]`
+// See https://github.com/raml-org/raml-spec/blob/master/versions/raml-10/raml-10.md/#type-expressions
+
+package godoc2api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kinds of type expressions
+const (
+	_EXPR_NAME    = iota // a named type, ex: Book, or an instance of a generic type, ex: Page[Book]
+	_EXPR_ARRAY          // an array, ex: Book[] or []Book
+	_EXPR_UNION          // a union, ex: Dog | Cat
+	_EXPR_MAP            // a map, ex: map[string]Book
+	_EXPR_POINTER        // a pointer, ex: *Book
+)
+
+// A parsed type expression
+type typeExpr struct {
+	kind  int
+	name  string
+	items []*typeExpr // the members of a union, the item of an array or a pointer, the key and the value of a map, the type arguments
+}
+
+// Parse a type expression
+func parseTypeExpr(s string) (*typeExpr, error) {
+	p := &typeExprParser{s: s}
+	e, err := p.union()
+	if err != nil {
+		return nil, err
+	}
+	p.spaces()
+	if p.pos < len(p.s) {
+		return nil, fmt.Errorf("unexpected `%s` in the type `%s`", p.s[p.pos:], s)
+	}
+	return e, nil
+}
+
+// Write the expression in a syntax that can be parsed again,
+// ex: `[](string | Person)` for `(string | Person)[]`
+func (e *typeExpr) String() string {
+	var group = func(item *typeExpr) string {
+		if item.kind == _EXPR_UNION {
+			return "(" + item.String() + ")"
+		}
+		return item.String()
+	}
+	switch e.kind {
+	case _EXPR_ARRAY:
+		return "[]" + group(e.items[0])
+	case _EXPR_POINTER:
+		return "*" + group(e.items[0])
+	case _EXPR_MAP:
+		return "map[" + e.items[0].String() + "]" + group(e.items[1])
+	case _EXPR_UNION:
+		members := []string{}
+		for _, item := range e.items {
+			members = append(members, item.String())
+		}
+		return strings.Join(members, " | ")
+	}
+	if len(e.items) > 0 {
+		// Written like `reflect` does, ex: Pair[string,Book]
+		args := []string{}
+		for _, item := range e.items {
+			args = append(args, item.String())
+		}
+		return e.name + "[" + strings.Join(args, ",") + "]"
+	}
+	return e.name
+}
+
+// Recursive descent parser of the type expressions:
+//	union   = postfix { "|" postfix }
+//	postfix = prefix { "[]" }
+//	prefix  = "*" prefix | "[" [ digits ] "]" prefix | "map[" union "]" prefix | "(" union ")" | name [ "[" union { "," union } "]" ]
+type typeExprParser struct {
+	s   string
+	pos int
+}
+
+func (p *typeExprParser) spaces() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// Consume a token if it's the next one
+func (p *typeExprParser) accept(token string) bool {
+	if strings.HasPrefix(p.s[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *typeExprParser) union() (*typeExpr, error) {
+	e, err := p.postfix()
+	if err != nil {
+		return nil, err
+	}
+	members := []*typeExpr{}
+	var add = func(e *typeExpr) {
+		if e.kind == _EXPR_UNION {
+			members = append(members, e.items...)
+		} else {
+			members = append(members, e)
+		}
+	}
+	add(e)
+	for {
+		p.spaces()
+		if !p.accept("|") {
+			break
+		}
+		e, err := p.postfix()
+		if err != nil {
+			return nil, err
+		}
+		add(e)
+	}
+	if len(members) == 1 {
+		return members[0], nil
+	}
+	return &typeExpr{kind: _EXPR_UNION, items: members}, nil
+}
+
+func (p *typeExprParser) postfix() (*typeExpr, error) {
+	e, err := p.prefix()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("[]") {
+		e = &typeExpr{kind: _EXPR_ARRAY, items: []*typeExpr{e}}
+	}
+	return e, nil
+}
+
+func (p *typeExprParser) prefix() (*typeExpr, error) {
+	p.spaces()
+	start := p.pos
+	switch {
+	case p.accept("*"):
+		e, err := p.prefix()
+		if err != nil {
+			return nil, err
+		}
+		return &typeExpr{kind: _EXPR_POINTER, items: []*typeExpr{e}}, nil
+	case p.accept("map["):
+		key, err := p.union()
+		if err != nil {
+			return nil, err
+		}
+		p.spaces()
+		if !p.accept("]") {
+			return nil, fmt.Errorf("missing `]` after the key of the map in the type `%s`", p.s)
+		}
+		value, err := p.prefix()
+		if err != nil {
+			return nil, err
+		}
+		return &typeExpr{kind: _EXPR_MAP, items: []*typeExpr{key, value}}, nil
+	case p.accept("["):
+		// Slices and arrays, ex: []Book or [3]Book
+		for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+			p.pos++
+		}
+		if !p.accept("]") {
+			return nil, fmt.Errorf("unexpected `%s` in the type `%s`", p.s[start:], p.s)
+		}
+		e, err := p.prefix()
+		if err != nil {
+			return nil, err
+		}
+		return &typeExpr{kind: _EXPR_ARRAY, items: []*typeExpr{e}}, nil
+	case p.accept("("):
+		e, err := p.union()
+		if err != nil {
+			return nil, err
+		}
+		p.spaces()
+		if !p.accept(")") {
+			return nil, fmt.Errorf("missing `)` in the type `%s`", p.s)
+		}
+		return e, nil
+	case p.accept("interface{}"):
+		return &typeExpr{kind: _EXPR_NAME, name: "interface{}"}, nil
+	}
+
+	// Names can be qualified by the path of their package, ex: github.com/me/api/models.Book
+	for p.pos < len(p.s) && strings.ContainsRune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_./-", rune(p.s[p.pos])) {
+		p.pos++
+	}
+	if p.pos == start {
+		if p.pos == len(p.s) {
+			return nil, fmt.Errorf("missing type at the end of `%s`", p.s)
+		}
+		return nil, fmt.Errorf("unexpected `%s` in the type `%s`", p.s[start:], p.s)
+	}
+	e := &typeExpr{kind: _EXPR_NAME, name: p.s[start:p.pos]}
+
+	// Type arguments, ex: Page[Book], but not Book[]
+	if strings.HasPrefix(p.s[p.pos:], "[") && !strings.HasPrefix(p.s[p.pos:], "[]") {
+		p.accept("[")
+		for {
+			arg, err := p.union()
+			if err != nil {
+				return nil, err
+			}
+			e.items = append(e.items, arg)
+			p.spaces()
+			if p.accept("]") {
+				break
+			}
+			if !p.accept(",") {
+				return nil, fmt.Errorf("missing `]` after the type arguments in the type `%s`", p.s)
+			}
+		}
+	}
+	return e, nil
+}
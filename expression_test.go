@@ -0,0 +1,42 @@
+package godoc2api
+
+import "testing"
+
+func TestParseTypeExpr(t *testing.T) {
+	cases := []struct {
+		expr     string
+		kind     int
+		expected string
+	}{
+		{"Book", _EXPR_NAME, "Book"},
+		{"Book[]", _EXPR_ARRAY, "[]Book"},
+		{"[]Book", _EXPR_ARRAY, "[]Book"},
+		{"[3]Book", _EXPR_ARRAY, "[]Book"},
+		{"*Book", _EXPR_POINTER, "*Book"},
+		{"Dog | Cat | nil", _EXPR_UNION, "Dog | Cat | nil"},
+		{"(string | Person)[]", _EXPR_ARRAY, "[](string | Person)"},
+		{"map[string][]Book", _EXPR_MAP, "map[string][]Book"},
+		{"Page[Book]", _EXPR_NAME, "Page[Book]"},
+		{"Pair[string, Book]", _EXPR_NAME, "Pair[string,Book]"},
+		{"github.com/me/api/models.Book", _EXPR_NAME, "github.com/me/api/models.Book"},
+		{"interface{}", _EXPR_NAME, "interface{}"},
+	}
+	for _, c := range cases {
+		e, err := parseTypeExpr(c.expr)
+		if err != nil {
+			t.Errorf("`%s`: unexpected error %v", c.expr, err)
+			continue
+		}
+		if e.kind != c.kind || e.String() != c.expected {
+			t.Errorf("`%s`: expected %d `%s`, got %d `%s`", c.expr, c.kind, c.expected, e.kind, e.String())
+		}
+	}
+}
+
+func TestParseTypeExprErrors(t *testing.T) {
+	for _, expr := range []string{"", "Dog |", "(Dog | Cat", "Page[Book", "Book]", "map[string"} {
+		if _, err := parseTypeExpr(expr); err == nil {
+			t.Errorf("`%s`: expected an error", expr)
+		}
+	}
+}
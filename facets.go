@@ -0,0 +1,257 @@
+// The facets are responsible for the types defined with standard RAML data types,
+// their properties being checked and rendered as RAML facets
+// See https://github.com/raml-org/raml-spec/blob/master/versions/raml-10/raml-10.md/#type-declarations
+
+package godoc2api
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/florenthobein/godoc2api/raml"
+)
+
+// Facets available by RAML type, the common ones excluded
+var raml_type_facets = map[string][]string{
+	"string":        {"pattern", "minLength", "maxLength"},
+	"number":        {"minimum", "maximum", "format", "multipleOf"},
+	"integer":       {"minimum", "maximum", "format", "multipleOf"},
+	"boolean":       {},
+	"array":         {"minItems", "maxItems", "uniqueItems", "items"},
+	"file":          {"fileTypes", "minLength", "maxLength"},
+	"date-only":     {},
+	"time-only":     {},
+	"datetime-only": {},
+	"datetime":      {"format"},
+	"object":        {"properties", "minProperties", "maxProperties", "additionalProperties", "discriminator", "discriminatorValue"},
+	"any":           {},
+	"nil":           {},
+}
+
+// Shorthands accepted by RAML type, rendered as standard facets,
+// ex: `length` for a `minLength` and a `maxLength` of the same value
+var raml_type_shorthands = map[string][]string{
+	"string": {"length"},
+}
+
+// Facets available for all the RAML types
+var raml_common_facets = []string{"description", "displayName", "example", "examples", "default", "enum"}
+
+// Formats available by RAML type
+var raml_type_formats = map[string][]string{
+	"number":   {"int", "int8", "int16", "int32", "int64", "long", "float", "double"},
+	"integer":  {"int", "int8", "int16", "int32", "int64", "long"},
+	"datetime": {"rfc3339", "rfc2616"},
+}
+
+// Create the RAML type of a type defined with a standard RAML data type,
+// the properties of the definition being rendered as facets.
+// The facets unknown for a built-in type and the values of a wrong type
+// are ignored with a warning.
+func (td *TypeDefinition) facetsToRAML() raml.Type {
+	t := raml.Type{Type: td.nameRAMLType}
+	f := facets{type_name: td.name, properties: td.properties}
+
+	// Unknown facets
+	allowed := map[string]bool{}
+	for _, name := range raml_common_facets {
+		allowed[name] = true
+	}
+	for _, name := range raml_type_facets[td.nameRAMLType] {
+		allowed[name] = true
+	}
+	for _, name := range raml_type_shorthands[td.nameRAMLType] {
+		allowed[name] = true
+	}
+	names := []string{}
+	for name := range td.properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	_, known := raml_type_facets[td.nameRAMLType]
+	for _, name := range names {
+		if known && !allowed[name] {
+			warn("unknown facet `%s` for the type `%s` of RAML type %s", name, td.name, td.nameRAMLType)
+		}
+	}
+
+	// Common facets
+	t.Description, _ = f.string("description")
+	t.DisplayName, _ = f.string("displayName")
+	t.Example = td.properties["example"]
+	t.Default = td.properties["default"]
+	if examples, ok := f.value("examples", reflect.Map); ok {
+		t.Examples = map[string]interface{}{}
+		for _, k := range examples.MapKeys() {
+			t.Examples[fmt.Sprint(k.Interface())] = examples.MapIndex(k).Interface()
+		}
+	}
+	if enum, ok := f.value("enum", reflect.Slice); ok {
+		for i := 0; i < enum.Len(); i++ {
+			t.Enum = append(t.Enum, enum.Index(i).Interface())
+		}
+	}
+
+	switch td.nameRAMLType {
+	case "string", "file":
+		if v, ok := f.string("pattern"); ok && td.nameRAMLType == "string" {
+			t.StringType.Pattern = &v
+		}
+		// Shorthand, overridden by the standard facets
+		if v, ok := f.int("length"); ok && td.nameRAMLType == "string" {
+			l := int(v)
+			t.StringType.MinLength, t.StringType.MaxLength = &l, &l
+		}
+		if v, ok := f.int("minLength"); ok {
+			l := int(v)
+			t.StringType.MinLength = &l
+		}
+		if v, ok := f.int("maxLength"); ok {
+			l := int(v)
+			t.StringType.MaxLength = &l
+		}
+		if v, ok := f.strings("fileTypes"); ok && td.nameRAMLType == "file" {
+			t.FileType.FileTypes = v
+		}
+	case "number", "integer":
+		if v, ok := f.float("minimum"); ok {
+			t.NumberType.Minimum = &v
+		}
+		if v, ok := f.float("maximum"); ok {
+			t.NumberType.Maximum = &v
+		}
+		if v, ok := f.int("multipleOf"); ok {
+			t.NumberType.MultipleOf = &v
+		}
+		t.NumberType.Format, _ = f.format(td.nameRAMLType)
+	case "datetime":
+		t.NumberType.Format, _ = f.format(td.nameRAMLType)
+	case "array":
+		t.ArrayType.MinItems, _ = f.int("minItems")
+		t.ArrayType.MaxItems, _ = f.int("maxItems")
+		t.ArrayType.UniqueItems, _ = f.bool("uniqueItems")
+		if v, ok := f.string("items"); ok {
+			t.ArrayType.Items = v
+		}
+	case "object":
+		if properties, ok := f.value("properties", reflect.Map); ok {
+			t.ObjectType.Properties = map[string]interface{}{}
+			for _, k := range properties.MapKeys() {
+				t.ObjectType.Properties[fmt.Sprint(k.Interface())] = properties.MapIndex(k).Interface()
+			}
+		}
+		t.ObjectType.MinProperties, _ = f.int("minProperties")
+		t.ObjectType.MaxProperties, _ = f.int("maxProperties")
+		t.ObjectType.AdditionalProperties, _ = f.bool("additionalProperties")
+		t.ObjectType.Discriminator, _ = f.string("discriminator")
+		t.ObjectType.DiscriminatorValue, _ = f.string("discriminatorValue")
+	}
+	return t
+}
+
+// Properties of a type definition, read with a check of their type
+type facets struct {
+	type_name  string
+	properties map[string]interface{}
+}
+
+// Warn about a property of a wrong type
+func (f facets) wrong(name string, expected string) {
+	warn("wrong value for the facet `%s` of the type `%s`: %v (%T), expected %s",
+		name, f.type_name, f.properties[name], f.properties[name], expected)
+}
+
+func (f facets) value(name string, kind reflect.Kind) (v reflect.Value, ok bool) {
+	raw, exists := f.properties[name]
+	if !exists {
+		return
+	}
+	v = reflect.ValueOf(raw)
+	if !v.IsValid() || v.Kind() != kind {
+		f.wrong(name, "a "+kind.String())
+		return v, false
+	}
+	return v, true
+}
+
+func (f facets) string(name string) (string, bool) {
+	v, ok := f.value(name, reflect.String)
+	if !ok {
+		return "", false
+	}
+	return v.String(), true
+}
+
+func (f facets) bool(name string) (bool, bool) {
+	v, ok := f.value(name, reflect.Bool)
+	if !ok {
+		return false, false
+	}
+	return v.Bool(), true
+}
+
+func (f facets) strings(name string) ([]string, bool) {
+	v, ok := f.value(name, reflect.Slice)
+	if !ok {
+		return nil, false
+	}
+	res := []string{}
+	for i := 0; i < v.Len(); i++ {
+		s, ok := v.Index(i).Interface().(string)
+		if !ok {
+			f.wrong(name, "a list of strings")
+			return nil, false
+		}
+		res = append(res, s)
+	}
+	return res, true
+}
+
+// Any number is accepted, as the values may come from a JSON or a YAML file
+func (f facets) float(name string) (float64, bool) {
+	raw, exists := f.properties[name]
+	if !exists {
+		return 0, false
+	}
+	v := reflect.ValueOf(raw)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	f.wrong(name, "a number")
+	return 0, false
+}
+
+// Any number without decimals is accepted
+func (f facets) int(name string) (int64, bool) {
+	v, ok := f.float(name)
+	if !ok {
+		return 0, false
+	}
+	if v != math.Trunc(v) {
+		f.wrong(name, "an integer")
+		return 0, false
+	}
+	return int64(v), true
+}
+
+// The format, among the ones of the RAML type
+func (f facets) format(raml_type string) (string, bool) {
+	v, ok := f.string("format")
+	if !ok {
+		return "", false
+	}
+	for _, format := range raml_type_formats[raml_type] {
+		if v == format {
+			return v, true
+		}
+	}
+	f.wrong("format", fmt.Sprintf("one of %v", raml_type_formats[raml_type]))
+	return "", false
+}
@@ -0,0 +1,68 @@
+package godoc2api
+
+import "testing"
+
+func TestFacetsTypes(t *testing.T) {
+	f := facets{type_name: "test", properties: map[string]interface{}{
+		"minimum":   3,
+		"maximum":   4.5,
+		"maxLength": 10.0,
+		"minLength": 2.5,
+		"pattern":   42,
+		"format":    "int64",
+		"unique":    true,
+		"fileTypes": []interface{}{"image/png", 1},
+	}}
+	if v, ok := f.float("minimum"); !ok || v != 3 {
+		t.Errorf("expected an int to be read as a number, got %v", v)
+	}
+	if v, ok := f.float("maximum"); !ok || v != 4.5 {
+		t.Errorf("expected a float to be read as a number, got %v", v)
+	}
+	if v, ok := f.int("maxLength"); !ok || v != 10 {
+		t.Errorf("expected a float without decimals to be read as an integer, got %v", v)
+	}
+	if _, ok := f.int("minLength"); ok {
+		t.Errorf("expected a float with decimals to be rejected as an integer")
+	}
+	if _, ok := f.string("pattern"); ok {
+		t.Errorf("expected a number to be rejected as a string")
+	}
+	if v, ok := f.bool("unique"); !ok || !v {
+		t.Errorf("expected a bool, got %v", v)
+	}
+	if _, ok := f.strings("fileTypes"); ok {
+		t.Errorf("expected a list with a number to be rejected as a list of strings")
+	}
+	if v, ok := f.format("integer"); !ok || v != "int64" {
+		t.Errorf("expected the format int64 for an integer, got %v", v)
+	}
+	if _, ok := f.format("datetime"); ok {
+		t.Errorf("expected the format int64 to be rejected for a datetime")
+	}
+	if _, ok := f.string("missing"); ok {
+		t.Errorf("expected a missing facet not to be read")
+	}
+}
+
+func TestFacetsToRAML(t *testing.T) {
+	td := &TypeDefinition{name: "code", nameRAMLType: "string", properties: map[string]interface{}{
+		"length":    4,
+		"maxLength": 6,
+		"pattern":   "^[A-Z]+$",
+		"minimum":   1,
+	}}
+	res := td.facetsToRAML()
+	if res.StringType.MinLength == nil || *res.StringType.MinLength != 4 {
+		t.Errorf("expected the minLength of the length shorthand, got %v", res.StringType.MinLength)
+	}
+	if res.StringType.MaxLength == nil || *res.StringType.MaxLength != 6 {
+		t.Errorf("expected the maxLength to override the length shorthand, got %v", res.StringType.MaxLength)
+	}
+	if res.StringType.Pattern == nil || *res.StringType.Pattern != "^[A-Z]+$" {
+		t.Errorf("expected the pattern, got %v", res.StringType.Pattern)
+	}
+	if res.NumberType.Minimum != nil {
+		t.Errorf("expected the minimum to be ignored for a string")
+	}
+}
@@ -0,0 +1,294 @@
+// The fields are responsible for listing the properties of a struct
+// the way `encoding/json` encodes them, embedded structs included
+
+package godoc2api
+
+import (
+	"go/ast"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Tag used to name a property, if the main tag is not defined
+const _ALT_TAG_NAME = "json"
+
+// A property of a struct, read by reflection or in the sources
+type structField struct {
+	name     string            // name of the property
+	options  string            // options of the tag, ex: `omitempty`
+	goName   string            // name of the go field
+	typeName string            // go type of the field, ex: `[]models.Book`
+	kind     reflect.Kind      // kind of the field, pointers excluded
+	tag      reflect.StructTag // tags of the field
+	rtype    reflect.Type      // type of the field, when read by reflection
+	comment  string            // comment of the field
+	depth    int               // nesting level of the field
+}
+
+// Read the name and the options of a field from its tags
+func fieldTag(tag reflect.StructTag) (name string, options string) {
+	value := tag.Get(_MAIN_TAG_NAME)
+	if value == "" {
+		value = tag.Get(_ALT_TAG_NAME)
+	}
+	v := strings.SplitN(value, ",", 2)
+	name = v[0]
+	if len(v) > 1 {
+		options = "," + v[1]
+	}
+	return
+}
+
+// Check if the options of a tag contain an option
+func hasTagOption(options, option string) bool {
+	for _, o := range strings.Split(options, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// List the properties of a struct.
+//
+// The fields of the embedded structs are promoted, following the rules of `encoding/json`:
+// the less nested field wins, otherwise the conflicting fields are ignored.
+// The fields tagged `,inline` are also promoted.
+//
+// The embedded structs that are explicitly defined types are not promoted
+// but returned as `bases`, to be rendered as an inheritance.
+func structFields(rt reflect.Type) (fields []structField, bases []string) {
+	type level struct {
+		typ   reflect.Type
+		depth int
+	}
+	next := []level{{typ: rt}}
+	visited := map[reflect.Type]bool{}
+	candidates := []structField{}
+
+	for len(next) > 0 {
+		l := next[0]
+		next = next[1:]
+		if visited[l.typ] {
+			continue
+		}
+		visited[l.typ] = true
+		comments := sourceFieldComments(l.typ.PkgPath(), l.typ.Name())
+
+		for i := 0; i < l.typ.NumField(); i++ {
+			f := l.typ.Field(i)
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if f.PkgPath != "" && !(f.Anonymous && ft.Kind() == reflect.Struct) {
+				// Unexported
+				continue
+			}
+			name, options := fieldTag(f.Tag)
+			if name == "-" && options == "" {
+				continue
+			}
+
+			// Embedded struct
+			if ft.Kind() == reflect.Struct && name == "" && (f.Anonymous || hasTagOption(options, "inline")) {
+				if base, ok := definedTypeName(ft); ok && l.depth == 0 && f.Anonymous && !embeds(ft, rt, nil) {
+					bases = append(bases, base)
+					continue
+				}
+				next = append(next, level{typ: ft, depth: l.depth + 1})
+				continue
+			}
+
+			// Only the tagged fields are described
+			if name == "" {
+				continue
+			}
+			candidates = append(candidates, structField{
+				name:     name,
+				options:  options,
+				goName:   f.Name,
+				typeName: goTypeName(f.Type),
+				kind:     ft.Kind(),
+				tag:      f.Tag,
+				rtype:    f.Type,
+				comment:  comments[f.Name],
+				depth:    l.depth,
+			})
+		}
+	}
+
+	return dominantFields(candidates), bases
+}
+
+// List the properties of a struct declared in the sources of a package,
+// following the same rules as `structFields`
+func sourceStructFields(pkg_path string, name string) (fields []structField, bases []string) {
+	type level struct {
+		pkg_path string
+		name     string
+		depth    int
+	}
+	next := []level{{pkg_path: pkg_path, name: name}}
+	visited := map[string]bool{}
+	candidates := []structField{}
+
+	for len(next) > 0 {
+		l := next[0]
+		next = next[1:]
+		if visited[l.pkg_path+"."+l.name] {
+			continue
+		}
+		visited[l.pkg_path+"."+l.name] = true
+		file, ts, _ := sourceTypeDecl(l.pkg_path, l.name)
+		if ts == nil {
+			continue
+		}
+		st, ok := ts.Type.(*ast.StructType)
+		if !ok {
+			continue
+		}
+		var type_args map[string]string
+		if l.depth == 0 {
+			// ex: `T` => `models.Book` for `Page[models.Book]`
+			type_args = sourceTypeArgs(ts, l.name)
+		}
+
+		for _, f := range st.Fields.List {
+			var tag reflect.StructTag
+			if f.Tag != nil {
+				if v, err := strconv.Unquote(f.Tag.Value); err == nil {
+					tag = reflect.StructTag(v)
+				}
+			}
+			name, options := fieldTag(tag)
+			if name == "-" && options == "" {
+				continue
+			}
+			kind := sourceKind(l.pkg_path, file, f.Type)
+			type_name := sourceTypeNameOf(l.pkg_path, file, f.Type, type_args)
+			if usesTypeParams(f.Type, type_args) {
+				kind = typeNameKind(type_name)
+			}
+
+			// Embedded struct
+			if kind == reflect.Struct && name == "" && (len(f.Names) == 0 || hasTagOption(options, "inline")) {
+				path, type_name := sourceTypeRef(l.pkg_path, file, f.Type)
+				if path == "" {
+					continue
+				}
+				if len(f.Names) == 0 && l.depth == 0 && (path != pkg_path || type_name != name) {
+					if base, ok := explicitTypeName(path + "." + type_name); ok {
+						bases = append(bases, base)
+						continue
+					}
+				}
+				next = append(next, level{pkg_path: path, name: type_name, depth: l.depth + 1})
+				continue
+			}
+
+			// Only the tagged fields are described
+			if name == "" {
+				continue
+			}
+			go_names := []string{}
+			for _, n := range f.Names {
+				if ast.IsExported(n.Name) {
+					go_names = append(go_names, n.Name)
+				}
+			}
+			if len(f.Names) == 0 {
+				go_names = append(go_names, typeExprName(f.Type))
+			}
+			comment := f.Doc.Text()
+			if comment == "" {
+				comment = f.Comment.Text()
+			}
+			for _, go_name := range go_names {
+				candidates = append(candidates, structField{
+					name:     name,
+					options:  options,
+					goName:   go_name,
+					typeName: type_name,
+					kind:     kind,
+					tag:      tag,
+					comment:  strings.Join(strings.Fields(comment), " "),
+					depth:    l.depth,
+				})
+			}
+		}
+	}
+
+	return dominantFields(candidates), bases
+}
+
+// Keep the dominant fields among the ones sharing a name:
+// the less nested one, if it is the only one at its level
+func dominantFields(candidates []structField) (fields []structField) {
+	depths := map[string]int{}
+	for _, f := range candidates {
+		if d, ok := depths[f.name]; !ok || f.depth < d {
+			depths[f.name] = f.depth
+		}
+	}
+	by_name := map[string][]structField{}
+	names := []string{}
+	for _, f := range candidates {
+		if f.depth != depths[f.name] {
+			continue
+		}
+		if _, ok := by_name[f.name]; !ok {
+			names = append(names, f.name)
+		}
+		by_name[f.name] = append(by_name[f.name], f)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if fs := by_name[name]; len(fs) == 1 {
+			fields = append(fields, fs[0])
+		}
+	}
+	return
+}
+
+// Name under which a go type has been explicitly defined
+func definedTypeName(rt reflect.Type) (string, bool) {
+	if name, ok := explicitTypeName(goTypeName(rt)); ok {
+		return name, true
+	}
+	if td, ok := isDefinedType(rt.Name()); ok && !td.discovered && td.reflectType != nil && *td.reflectType == rt {
+		return td.name, true
+	}
+	return "", false
+}
+
+// Check if a struct embeds a type, directly or not.
+// Inheriting from such a struct would create a cycle.
+func embeds(rt reflect.Type, target reflect.Type, visited map[reflect.Type]bool) bool {
+	if rt == target {
+		return true
+	}
+	if visited == nil {
+		visited = map[reflect.Type]bool{}
+	}
+	if visited[rt] {
+		return false
+	}
+	visited[rt] = true
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if !f.Anonymous {
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && embeds(ft, target, visited) {
+			return true
+		}
+	}
+	return false
+}
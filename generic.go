@@ -0,0 +1,200 @@
+// The generic is responsible for the instances of the generic types,
+// like `Page[Book]`, rendered as `PageOfBook` inheriting from `Page`
+
+package godoc2api
+
+import (
+	"fmt"
+	"go/ast"
+	"reflect"
+	"strings"
+)
+
+// Name of the RAML type of an instance of a generic type,
+// ex: `PageOfBook` for `Page[Book]`, `PairOfStringAndArrayBook` for `Pair[string, []Book]`
+func genericName(e *typeExpr) (string, error) {
+	base := e.name[strings.LastIndex(e.name, ".")+1:]
+	if i := strings.LastIndex(e.name, "."); i >= 0 {
+		discoverSourceType(e.name[:i], e.name[i+1:])
+	}
+	if alias, ok := isTypeAlias(e.name); ok {
+		base = alias
+	} else if td, ok := isDefinedType(e.name); ok {
+		base = td.name
+	}
+	args := []string{}
+	for _, arg := range e.items {
+		_, t, _, err := formatTypeExpr(arg)
+		if err != nil {
+			return "", fmt.Errorf("%v in the type argument of %s", err, e.String())
+		}
+		part := typeNamePart(t)
+		if part == "" {
+			return "", fmt.Errorf("missing type argument in %s", e.String())
+		}
+		args = append(args, strings.ToUpper(part[:1])+part[1:])
+	}
+	name := base + "Of" + strings.Join(args, "And")
+
+	// Another go type may already use the name
+	for i := 2; ; i++ {
+		td, ok := isDefinedType(name)
+		if !ok || (td.goName == e.String() && td.aliasFor == nil) {
+			return name, nil
+		}
+		name = fmt.Sprintf("%sOf%s%d", base, strings.Join(args, "And"), i)
+	}
+}
+
+// Define an instance of a generic type declared in the sources of a package,
+// and return its full name, ex: `github.com/me/api/models.Page[github.com/me/api/models.Book]`
+func discoverGenericType(e *typeExpr) (string, error) {
+	full := e.String()
+	if _, ok := isDefinedType(full); ok {
+		return full, nil
+	}
+	i := strings.LastIndex(e.name, ".")
+	if i < 0 {
+		return "", fmt.Errorf("generic type %s not found", full)
+	}
+	pkg_path, name := e.name[:i], e.name[i+1:]
+	_, ts, _ := sourceTypeDecl(pkg_path, name)
+	if ts == nil || ts.TypeParams == nil {
+		return "", fmt.Errorf("generic type %s not found", full)
+	}
+	generic_name, err := genericName(e)
+	if err != nil {
+		return "", err
+	}
+	ref := &sourceRef{pkgPath: pkg_path, name: full[i+1:]}
+	storeDiscoveredType(full, TypeDefinition{name: generic_name, sourceType: ref})
+	debug("discovery of type %s in the sources", full)
+	return full, nil
+}
+
+// Arguments of a generic type declaration, identified by the name of the parameters,
+// ex: `T` => `models.Book` for `Page[models.Book]`.
+// The parameters without argument are considered as `interface{}`.
+func sourceTypeArgs(ts *ast.TypeSpec, name string) map[string]string {
+	if ts.TypeParams == nil {
+		return nil
+	}
+	var items []*typeExpr
+	if e, err := parseTypeExpr(name); err == nil && e.kind == _EXPR_NAME {
+		items = e.items
+	}
+	args := map[string]string{}
+	i := 0
+	for _, field := range ts.TypeParams.List {
+		for _, param := range field.Names {
+			args[param.Name] = "interface{}"
+			if i < len(items) {
+				args[param.Name] = items[i].String()
+			}
+			i++
+		}
+	}
+	return args
+}
+
+// Go names of the fields of a generic struct which type depends on the type parameters,
+// ex: `Items` for `type Page[T any] struct { Items []T; Total int }`
+func sourceGenericFields(pkg_path, name string) (map[string]bool, bool) {
+	_, ts, _ := sourceTypeDecl(pkg_path, name)
+	if ts == nil || ts.TypeParams == nil {
+		return nil, false
+	}
+	st, ok := ts.Type.(*ast.StructType)
+	if !ok {
+		return nil, false
+	}
+	args := sourceTypeArgs(ts, name)
+	dependent := map[string]bool{}
+	for _, f := range st.Fields.List {
+		if !usesTypeParams(f.Type, args) {
+			continue
+		}
+		for _, n := range f.Names {
+			dependent[n.Name] = true
+		}
+		if len(f.Names) == 0 {
+			dependent[typeExprName(f.Type)] = true
+		}
+	}
+	return dependent, true
+}
+
+// Check if a type expression references type parameters
+func usesTypeParams(expr ast.Expr, params map[string]string) (uses bool) {
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			if _, ok := params[id.Name]; ok {
+				uses = true
+			}
+		}
+		return !uses
+	})
+	return
+}
+
+// Kind of a go type written like `reflect` would, ex: `[]models.Book` => slice
+func typeNameKind(type_name string) reflect.Kind {
+	e, err := parseTypeExpr(type_name)
+	if err != nil {
+		return reflect.Invalid
+	}
+	for e.kind == _EXPR_POINTER {
+		e = e.items[0]
+	}
+	switch e.kind {
+	case _EXPR_ARRAY:
+		return reflect.Slice
+	case _EXPR_MAP:
+		return reflect.Map
+	case _EXPR_UNION:
+		return reflect.Interface
+	}
+	if e.name == "interface{}" {
+		return reflect.Interface
+	}
+	if is_scalar, raml_type := isScalar(e.String()); is_scalar {
+		return scalarKind(raml_type)
+	}
+	return reflect.Struct
+}
+
+// Split the properties of a generic struct: an instance keeps the properties
+// depending on the type arguments and inherits the others from the generic type,
+// which only renders the latter, ex: `PageOfBook` inherits `total` from `Page`.
+// The name of an instance includes its type arguments, ex: `Page[models.Book]`.
+// The instances of the generic types without such properties are not split.
+func genericInheritance(pkg_path, name string, fields []structField, bases []string) ([]structField, []string) {
+	base_name := strings.SplitN(name, "[", 2)[0]
+	dependent, ok := sourceGenericFields(pkg_path, base_name)
+	if !ok {
+		return fields, bases
+	}
+	own, inherited := []structField{}, []structField{}
+	for _, f := range fields {
+		if f.depth == 0 && dependent[f.goName] {
+			own = append(own, f)
+		} else {
+			inherited = append(inherited, f)
+		}
+	}
+	if base_name == name {
+		return inherited, bases
+	}
+	if len(inherited) == 0 {
+		return fields, bases
+	}
+	full, ok := discoverSourceType(pkg_path, base_name)
+	if !ok {
+		return fields, bases
+	}
+	if alias, ok := isTypeAlias(full); ok {
+		full = alias
+	}
+	// The other inherited types are inherited by the generic type
+	return own, []string{full}
+}
@@ -0,0 +1,23 @@
+package godoc2api
+
+import (
+	"strings"
+	"testing"
+)
+
+type testPage[T any] struct {
+	Items []T `json:"items"`
+}
+
+type testBook struct{}
+
+func TestGenericUnknownTypeArgument(t *testing.T) {
+	pkg_path := "github.com/florenthobein/godoc2api"
+	_, _, _, err := formatType(pkg_path + ".testPage[Unknown[" + pkg_path + ".testBook]]")
+	if err == nil || !strings.Contains(err.Error(), "Unknown") {
+		t.Errorf("expected an error for the unknown type argument, got %v", err)
+	}
+	if _, _, _, err := formatType(pkg_path + ".testPage[" + pkg_path + ".testBook]"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
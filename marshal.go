@@ -0,0 +1,187 @@
+// The marshal is responsible for describing the types which JSON encoding
+// is customized, ex: a `Money` struct encoded as `"12.30 EUR"`
+
+package godoc2api
+
+import (
+	"database/sql"
+	"encoding"
+	"encoding/json"
+	"go/ast"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/florenthobein/godoc2api/raml"
+)
+
+// Interface of the types describing their own RAML type,
+// when their JSON encoding differs from their go layout.
+//
+// Example
+//	func (Money) RAMLType() raml.Type {
+//		return raml.Type{Type: "string", Example: "12.30 EUR"}
+//	}
+type RAMLTyper interface {
+	RAMLType() raml.Type
+}
+
+// Registry of the RAML types of the go types which encoding is customized.
+// It starts with the types of the standard library that are encoded as objects
+// of untagged fields, ex: `sql.NullString` as `{"String": "", "Valid": false}`
+var index_wire_types = map[reflect.Type]raml.Type{
+	reflect.TypeOf(url.URL{}): {Type: "object", ObjectType: raml.ObjectType{Properties: map[string]interface{}{
+		"Scheme":      "string",
+		"Opaque":      "string",
+		"User":        "object | nil",
+		"Host":        "string",
+		"Path":        "string",
+		"RawPath":     "string",
+		"OmitHost":    "boolean",
+		"ForceQuery":  "boolean",
+		"RawQuery":    "string",
+		"Fragment":    "string",
+		"RawFragment": "string",
+	}}},
+	reflect.TypeOf(sql.NullString{}):  nullType("String", "string"),
+	reflect.TypeOf(sql.NullBool{}):    nullType("Bool", "boolean"),
+	reflect.TypeOf(sql.NullByte{}):    nullType("Byte", "integer"),
+	reflect.TypeOf(sql.NullInt16{}):   nullType("Int16", "integer"),
+	reflect.TypeOf(sql.NullInt32{}):   nullType("Int32", "integer"),
+	reflect.TypeOf(sql.NullInt64{}):   nullType("Int64", "integer"),
+	reflect.TypeOf(sql.NullFloat64{}): nullType("Float64", "number"),
+	reflect.TypeOf(sql.NullTime{}):    nullType("Time", "datetime"),
+}
+
+// RAML type of a `sql.Null*` type, encoded with its value and its validity
+func nullType(field string, t string) raml.Type {
+	return raml.Type{Type: "object", ObjectType: raml.ObjectType{Properties: map[string]interface{}{
+		field:   t,
+		"Valid": "boolean",
+	}}}
+}
+
+var (
+	_JSON_MARSHALER = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	_TEXT_MARSHALER = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	_RAML_TYPER     = reflect.TypeOf((*RAMLTyper)(nil)).Elem()
+)
+
+// Configure the RAML type of a go type as encoded in JSON,
+// overriding the one that is detected.
+//
+// Example
+//	DefineWireType(uuid.UUID{}, raml.Type{Type: "string", Description: "UUID v4"})
+func DefineWireType(obj interface{}, t raml.Type) {
+	if index_wire_types == nil {
+		index_wire_types = make(map[reflect.Type]raml.Type)
+	}
+	rt := reflect.TypeOf(obj)
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	index_wire_types[rt] = t
+}
+
+// RAML type of a go type as encoded in JSON, if its encoding is customized:
+// by a definition, by the `RAMLType` method, by the `MarshalJSON` method
+// (the zero value is encoded to know the kind of JSON produced, the marshalers
+// that fail or panic on it being considered as producing any JSON)
+// or by the `MarshalText` method.
+// The marshalers of the named strings are ignored, they usually encode themselves.
+func wireType(rt reflect.Type) (t raml.Type, ok bool) {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if t, ok := index_wire_types[rt]; ok {
+		return t, true
+	}
+	ptr := reflect.PtrTo(rt)
+	switch {
+	case ptr.Implements(_RAML_TYPER):
+		defer func() {
+			if recover() != nil {
+				t, ok = raml.Type{}, false
+			}
+		}()
+		return reflect.New(rt).Interface().(RAMLTyper).RAMLType(), true
+	case rt.Kind() == reflect.String:
+		return
+	case ptr.Implements(_JSON_MARSHALER):
+		t = raml.Type{Type: sampleJSONType(rt)}
+	case ptr.Implements(_TEXT_MARSHALER):
+		t = raml.Type{Type: "string"}
+	default:
+		return
+	}
+	_, doc := sourceTypeSpec(rt.PkgPath(), rt.Name())
+	t.Description = strings.Join(strings.Fields(doc.Text()), " ")
+	return t, true
+}
+
+// RAML type of a type declared in the sources, if its encoding is customized
+// by the `MarshalJSON` or `MarshalText` methods
+func (s *sourceRef) wireType() (t raml.Type, ok bool) {
+	file, ts, doc := sourceTypeDecl(s.pkgPath, s.name)
+	if ts == nil || sourceKind(s.pkgPath, file, ts.Type) == reflect.String {
+		return
+	}
+	methods := sourceMethods(s.pkgPath, strings.SplitN(s.name, "[", 2)[0])
+	switch {
+	case methods["MarshalJSON"]:
+		// The kind of JSON produced can't be known without running it
+		t = raml.Type{Type: "any"}
+	case methods["MarshalText"]:
+		t = raml.Type{Type: "string"}
+	default:
+		return
+	}
+	t.Description = strings.Join(strings.Fields(doc.Text()), " ")
+	return t, true
+}
+
+// Names of the methods declared with a type in the sources of its package
+func sourceMethods(pkg_path, name string) map[string]bool {
+	methods := map[string]bool{}
+	for _, f := range sourcePackage(pkg_path) {
+		for _, decl := range f.Decls {
+			if fd, ok := decl.(*ast.FuncDecl); ok && receiverName(fd) == name {
+				methods[fd.Name.Name] = true
+			}
+		}
+	}
+	return methods
+}
+
+// Kind of JSON produced by the `MarshalJSON` method of the zero value of a type.
+// The method being user code, a panic falls back to `any`.
+func sampleJSONType(rt reflect.Type) (res string) {
+	res = "any"
+	defer func() {
+		if recover() != nil {
+			res = "any"
+		}
+	}()
+	b, err := reflect.New(rt).Interface().(json.Marshaler).MarshalJSON()
+	if err != nil {
+		return
+	}
+	s := strings.TrimLeft(string(b), " \t\r\n")
+	if s == "" {
+		return
+	}
+	switch s[0] {
+	case '"':
+		return "string"
+	case 't', 'f':
+		return "boolean"
+	case '[':
+		return "array"
+	case '{':
+		return "object"
+	case 'n':
+		// null says nothing about the other values
+		return
+	}
+	return "number"
+}
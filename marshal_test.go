@@ -0,0 +1,30 @@
+package godoc2api
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Encoded as a string, but panics on its zero value
+type fragileMarshaler struct {
+	value *string
+}
+
+func (f fragileMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + *f.value + `"`), nil
+}
+
+type stringMarshaler struct{}
+
+func (stringMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`"value"`), nil
+}
+
+func TestSampleJSONType(t *testing.T) {
+	if res := sampleJSONType(reflect.TypeOf(stringMarshaler{})); res != "string" {
+		t.Errorf("expected string, got %s", res)
+	}
+	if res := sampleJSONType(reflect.TypeOf(fragileMarshaler{})); res != "any" {
+		t.Errorf("expected any for a marshaler panicking on the zero value, got %s", res)
+	}
+}
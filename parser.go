@@ -5,9 +5,19 @@ package godoc2api
 
 import (
 	"fmt"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
+)
+
+// Struct tags locating the parameters of a request struct
+const (
+	_REQUEST_URI    = "uri"
+	_REQUEST_PATH   = "path" // alias for uri
+	_REQUEST_QUERY  = "query"
+	_REQUEST_HEADER = "header"
 )
 
 // Regular expressions used for parsing the comments
@@ -16,13 +26,19 @@ const (
 	_PARSE_RESOURCE        = `^(?:` + _PARSE_METHODS + ` )?(/.+)$`
 	_PARSE_TAG             = `^(?://| ?\*) @(\w+)(?:[ 	]+(.+))?$`
 	_PARSE_TAGBLOCK        = `^(?://| ?\*)(?:[ 	]+(.+))?$`
-	_PARSE_LINE            = `^\{\(?([^\)]+)\)?\}(?:[ 	]+\[?([\w\=]+)?\]?(?:[ 	\-]+(?:\-[ 	]+)?(.+))?)?$`
-	_PARSE_TYPE            = `^([\w \|\[\]\{\}]+)(?:\:([\w\|\,]+))?$`
+	_PARSE_LINE            = `^\{((?:[^\{\}]|\{\})+)\}(?:[ 	]+\[?([\w\=]+)?\]?(?:[ 	\-]+(?:\-[ 	]+)?(.+))?)?$`
+	_PARSE_TYPE            = `^([\w \|\[\]\{\}\(\)\.\*\/\-\,]+)(?:\:([\w\|\,]+))?$`
 	_PARSE_TYPE_ENUM       = ` *\| *`
 	_PARSE_TYPE_COMBINABLE = ` *\, *`
 	_PARSE_NAME            = `^(\w+)(?:\=(\w+))?$`
+	_PARSE_STATUS          = `^(\d{3})(?:[ 	]+(.*))?$`
+	_PARSE_MEDIA_TYPE      = `^((?i:` + _PARSE_MEDIA_TOP_TYPES + `)/[\w\.\+\-]+)(?:[ 	]+(.*))?$`
 )
 
+// Top-level media types registered by the IANA, only the words starting with
+// one of them being read as media types, not ex: `and/or`
+const _PARSE_MEDIA_TOP_TYPES = `application|audio|font|haptics|image|message|model|multipart|text|video`
+
 // Analyse a comment to extract the keywords
 func parseComment(comment string) map[string][][]string {
 
@@ -97,6 +113,58 @@ func parseResource(str string) (resource string, eventual_method string, err err
 	return
 }
 
+// Parse the path template of a router into a resource.
+// Patterns of the parameters are removed, ex: `/books/{id:[0-9]+}` => `/books/{id}`,
+// as well as the wildcards of `http.ServeMux`, ex: `/files/{path...}` => `/files/{path}`
+func parseRouterPath(str string) (resource string, err error) {
+	if str == "" || str[0] != '/' {
+		return "", fmt.Errorf("resources should be relative and start by a /")
+	}
+	depth, param := 0, ""
+	for _, c := range str {
+		switch {
+		case c == '{':
+			depth++
+			if depth == 1 {
+				param = ""
+				continue
+			}
+		case c == '}':
+			depth--
+			if depth == 0 {
+				param = strings.TrimSuffix(strings.SplitN(param, ":", 2)[0], "...")
+				if param != "$" {
+					resource += "{" + param + "}"
+				}
+				continue
+			}
+		}
+		if depth > 0 {
+			param += string(c)
+		} else {
+			resource += string(c)
+		}
+	}
+	if depth != 0 {
+		return "", fmt.Errorf("unbalanced braces in path `%s`", str)
+	}
+	if len(resource) > 1 {
+		resource = strings.TrimRight(resource, "/")
+	}
+	return
+}
+
+// Parse a pattern of `http.ServeMux`, ex: `GET example.com/books/{id}`
+func parseServeMuxPattern(str string) (method string, path string, err error) {
+	res := regexp.
+		MustCompile(`^(?:` + _PARSE_METHODS + `[ 	]+)?[^/ 	]*(/.*)$`).
+		FindStringSubmatch(strings.Trim(str, " \t"))
+	if len(res) != 3 {
+		return "", "", fmt.Errorf("unsupported pattern `%s`", str)
+	}
+	return res[1], res[2], nil
+}
+
 // Parse the description
 func parseDescription(arr []string) (description string, eventual_title string, err error) {
 
@@ -145,7 +213,8 @@ func parseParameter(arr []string, is_body bool) (p Parameter, register_types []T
 	// Define attributes
 	type_name, name, description := "", "", ""
 	if is_body {
-		type_name, description = arr[1], arr[2]
+		// A body has no name, its first word being part of the description
+		type_name, description = arr[1], strings.Trim(arr[2]+" "+arr[3], " ")
 	} else {
 		type_name, name, description = arr[1], arr[2], arr[3]
 	}
@@ -177,6 +246,7 @@ func parseParameter(arr []string, is_body bool) (p Parameter, register_types []T
 		Name:        name,
 		Type:        Type(type_name),
 		Description: description,
+		goType:      type_name,
 	}
 
 	// Check possible values for type
@@ -207,33 +277,14 @@ func parseParameter(arr []string, is_body bool) (p Parameter, register_types []T
 	}
 
 	// If enum, parse to the good type
-	var parseList = func(list []string, to string) (res []interface{}) {
-		res = []interface{}{}
-		for _, s := range list {
-			var v interface{}
-			var err error
-			switch to {
-			case "number":
-				v, err = strconv.ParseFloat(s, 64)
-			case "integer":
-				v, err = strconv.Atoi(s)
-			case "string":
-				v = s
-			}
-			if err == nil {
-				res = append(res, v)
-			}
-		}
-		return
-	}
 	if len(enum) != 0 {
-		p.Enum = parseList(enum, string(p.Type))
-		p.Example = strings.Join(examples, ", ") //parseList(examples, string(p.Type))
+		p.Enum = parseValues(enum, string(p.Type))
+		p.Example = strings.Join(examples, ", ") //parseValues(examples, string(p.Type))
 	}
 
 	// If default, parse to the good type
 	if type_default != "" {
-		val := parseList([]string{type_default}, string(p.Type))
+		val := parseValues([]string{type_default}, string(p.Type))
 		if len(val) > 0 {
 			p.Default = val[0]
 		}
@@ -242,6 +293,132 @@ func parseParameter(arr []string, is_body bool) (p Parameter, register_types []T
 	return
 }
 
+// Parse a list of values to the good type
+func parseValues(list []string, to string) (res []interface{}) {
+	res = []interface{}{}
+	for _, s := range list {
+		var v interface{}
+		var err error
+		switch to {
+		case "number":
+			v, err = strconv.ParseFloat(s, 64)
+		case "integer":
+			v, err = strconv.Atoi(s)
+		case "string":
+			v = s
+		}
+		if err == nil {
+			res = append(res, v)
+		}
+	}
+	return
+}
+
+// Parse a reference to a request struct, ex: `{ListBooksRequest}`
+func parseRequestType(arr []string) (rt reflect.Type, err error) {
+	line := strings.Trim(strings.Join(arr, " "), " \t")
+	res := regexp.MustCompile(_PARSE_LINE).FindStringSubmatch(line)
+	if len(res) == 0 || res[2] != "" {
+		return nil, fmt.Errorf("wrong request definition")
+	}
+	td, ok := isDefinedType(res[1])
+	if ok && td.aliasFor != nil {
+		td = *td.aliasFor
+	}
+	if !ok || td.reflectType == nil {
+		return nil, fmt.Errorf("type `%s` not found", res[1])
+	}
+	rt = *td.reflectType
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("type `%s` is not a struct", res[1])
+	}
+	return rt, nil
+}
+
+// Parse the fields of a request struct into parameters, according to their tags:
+//	type ListBooksRequest struct {
+//		Limit int    `query:"limit" default:"10" description:"Number of books"`
+//		Sort  string `query:"sort" enum:"asc,desc"`
+//		Token string `header:"X-Token"`
+//		Id    string `uri:"id"`
+//	}
+// The fields tagged `json` (or `raml`) are part of the body.
+func parseRequest(rt reflect.Type) (ps map[string][]Parameter, has_body bool, register_types []Type, err error) {
+	ps = map[string][]Parameter{}
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+
+		// Embedded request structs
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && ft.Kind() == reflect.Struct {
+			other_ps, other_body, other_ts, err := parseRequest(ft)
+			if err != nil {
+				return nil, false, nil, err
+			}
+			for in, list := range other_ps {
+				ps[in] = append(ps[in], list...)
+			}
+			has_body = has_body || other_body
+			register_types = append(register_types, other_ts...)
+			continue
+		}
+
+		// Find where the parameter is located
+		in, name := "", ""
+		for _, location := range []string{_REQUEST_URI, _REQUEST_PATH, _REQUEST_QUERY, _REQUEST_HEADER} {
+			if v := strings.Split(f.Tag.Get(location), ",")[0]; v != "" && v != "-" {
+				in, name = location, v
+				break
+			}
+		}
+		if in == _REQUEST_PATH {
+			in = _REQUEST_URI
+		}
+		if in == "" {
+			if v := f.Tag.Get(_MAIN_TAG_NAME) + f.Tag.Get("json"); v != "" && v != "-" {
+				has_body = true
+			}
+			continue
+		}
+
+		// Format the type
+		type_name := goTypeName(f.Type)
+		if v := f.Tag.Get("ramlType"); v != "" {
+			type_name = v
+		} else {
+			discoverType(f.Type)
+		}
+		_, precise, other_ts, err := formatType(type_name)
+		if err != nil {
+			return nil, false, nil, err
+		}
+		register_types = append(register_types, other_ts...)
+
+		p := Parameter{
+			Name:        name,
+			Type:        precise,
+			Description: f.Tag.Get("description"),
+			goType:      type_name,
+		}
+		if v := f.Tag.Get("enum"); v != "" {
+			p.Enum = parseValues(regexp.MustCompile(_PARSE_TYPE_COMBINABLE+"|"+_PARSE_TYPE_ENUM).Split(v, -1), string(precise))
+		}
+		if v := f.Tag.Get("default"); v != "" {
+			if val := parseValues([]string{v}, string(precise)); len(val) > 0 {
+				p.Default = val[0]
+			}
+		}
+		ps[in] = append(ps[in], p)
+	}
+	return
+}
+
 // Parse a response
 func parseResponse(arr []string) (r Response, register_types []Type, err error) {
 	if len(arr) == 0 {
@@ -281,6 +458,27 @@ func parseResponse(arr []string) (r Response, register_types []Type, err error)
 	return
 }
 
+// Parse the status and the media type eventually preceding a body or a response,
+// ex: `200 text/csv {string} The report` => 200, text/csv, `{string} The report`.
+// The status is 200 if it's not given, and the media type empty.
+func parseStatusAndMediaType(arr []string, with_status bool) (status int, media_type string, rest []string) {
+	status = 200
+	if len(arr) == 0 {
+		return status, media_type, arr
+	}
+	rest = append([]string{}, arr...)
+	rest[0] = strings.Trim(rest[0], " \t")
+	if res := regexp.MustCompile(_PARSE_STATUS).FindStringSubmatch(rest[0]); with_status && len(res) > 0 {
+		status, _ = strconv.Atoi(res[1])
+		rest[0] = res[2]
+	}
+	if res := regexp.MustCompile(_PARSE_MEDIA_TYPE).FindStringSubmatch(rest[0]); len(res) > 0 {
+		media_type = res[1]
+		rest[0] = res[2]
+	}
+	return
+}
+
 // Parse an example
 func parseExample(att []string) (e Example, err error) {
 	if len(att) == 0 {
@@ -354,7 +552,11 @@ func parseExample(att []string) (e Example, err error) {
 }
 
 func parseTrait(tag string, value interface{}) (t Trait, err error) {
-	// todo
+	if !isReservedTrait(tag) {
+		return Trait{}, fmt.Errorf("trait `%s` not defined", tag)
+	}
+	// todo value
+	t = index_traits[tag]
 	return
 }
 func parseSecurity(tag string) (s Security, err error) {
@@ -364,6 +566,40 @@ func parseSecurity(tag string) (s Security, err error) {
 	s = index_securities[tag]
 	return
 }
+
+// Parse protocols separated by spaces or commas, ex: `http, https` => HTTP, HTTPS,
+// without duplicates
+func parseProtocols(lines []string) (protocols []string, err error) {
+	for _, l := range lines {
+		for _, p := range strings.FieldsFunc(l, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		}) {
+			p = strings.ToUpper(p)
+			if p != "HTTP" && p != "HTTPS" {
+				return nil, fmt.Errorf("unknown protocol `%s`, HTTP or HTTPS expected", p)
+			}
+			found := false
+			for _, known := range protocols {
+				found = found || known == p
+			}
+			if !found {
+				protocols = append(protocols, p)
+			}
+		}
+	}
+	return
+}
+
+// Parse the scopes required by a security, separated by spaces or commas
+func parseScopes(lines []string) (scopes []string) {
+	for _, l := range lines {
+		scopes = append(scopes, strings.FieldsFunc(l, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})...)
+	}
+	return
+}
+
 func parseAnnotation(tag string, value interface{}) (a Annotation, err error) {
 	// todo
 	return
@@ -0,0 +1,42 @@
+package godoc2api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStatusAndMediaType(t *testing.T) {
+	cases := []struct {
+		line        string
+		with_status bool
+		status      int
+		media_type  string
+		rest        string
+	}{
+		{"{MyStruct} The statistics", true, 200, "", "{MyStruct} The statistics"},
+		{"201 {MyStruct}", true, 201, "", "{MyStruct}"},
+		{"200 text/csv {string} The report", true, 200, "text/csv", "{string} The report"},
+		{"multipart/form-data {UploadForm}", false, 200, "multipart/form-data", "{UploadForm}"},
+		{"application/vnd.api+json {Book}", false, 200, "application/vnd.api+json", "{Book}"},
+		{"404 and/or the shelf is missing", true, 404, "", "and/or the shelf is missing"},
+		{"TCP/IP error", true, 200, "", "TCP/IP error"},
+		{"201 {MyStruct}", false, 200, "", "201 {MyStruct}"},
+	}
+	for _, c := range cases {
+		status, media_type, rest := parseStatusAndMediaType([]string{c.line}, c.with_status)
+		if status != c.status || media_type != c.media_type || rest[0] != c.rest {
+			t.Errorf("`%s`: expected %d, %s, `%s`, got %d, %s, `%s`",
+				c.line, c.status, c.media_type, c.rest, status, media_type, rest[0])
+		}
+	}
+}
+
+func TestParseProtocols(t *testing.T) {
+	protocols, err := parseProtocols([]string{"http, HTTPS", "https http"})
+	if err != nil || !reflect.DeepEqual(protocols, []string{"HTTP", "HTTPS"}) {
+		t.Errorf("expected HTTP and HTTPS, got %v (%v)", protocols, err)
+	}
+	if _, err := parseProtocols([]string{"HTTP ws"}); err == nil {
+		t.Errorf("expected an error for the protocol ws")
+	}
+}
@@ -35,8 +35,7 @@ type Method struct {
 	QueryParameters map[string]Type `yaml:"queryParameters,omitempty"`
 
 	// Detailed information about any request headers needed by this method.
-	// TODO
-	//////// Headers map[string]Header `yaml:"headers,omitempty"`
+	Headers map[string]Type `yaml:"headers,omitempty"`
 
 	// The query string needed by this method.
 	// Mutually exclusive with queryParameters.
@@ -49,17 +48,17 @@ type Method struct {
 	Responses map[HTTPCode]Response `yaml:"responses,omitempty"`
 
 	// A request body that the method admits.
-	Body *Body `yaml:"body,omitempty"`
+	Body Body `yaml:"body,omitempty"`
 
 	// Explicitly specify the protocol(s) used to invoke a method,
 	// thereby overriding the protocols set elsewhere,
 	// for example in the baseUri or the root-level protocols property.
-	// TODO
-	//////// Protocols []string `yaml:"protocols,omitempty"`
+	Protocols []string `yaml:"protocols,flow,omitempty"`
 
 	// A list of the traits to apply to this method.
 	Is []string `yaml:"is,flow,omitempty"`
 
 	// The security schemes that apply to this method.
-	SecuredBy []string `yaml:"securedBy,flow,omitempty"`
+	// The value is the name of a security scheme, a `SecuredBy` or nil for no security.
+	SecuredBy []interface{} `yaml:"securedBy,flow,omitempty"`
 }
@@ -50,11 +50,12 @@ type Resource struct {
 	Is []string `yaml:"is,flow,omitempty"`
 
 	// The resource type that this resource inherits.
-	// TODO
-	//////// Type interface{} `yaml:"type,omitempty"`
+	// The value is the name of a resource type, or a map of its name to its parameters.
+	Type interface{} `yaml:"type,flow,omitempty"`
 
 	// The security schemes that apply to all methods declared (implicitly or explicitly) for this resource.
-	SecuredBy []string `yaml:"securedBy,flow,omitempty"`
+	// The value is the name of a security scheme, a `SecuredBy` or nil for no security.
+	SecuredBy []interface{} `yaml:"securedBy,flow,omitempty"`
 
 	// A resource defined as a child node of another resource is called a nested resource.
 	// The key of the child node is the URI of the nested resource relative to the
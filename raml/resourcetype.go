@@ -0,0 +1,28 @@
+// Resource types
+//
+// Inspired by RAML 1.0 specs
+// https://github.com/raml-org/raml-spec/blob/master/versions/raml-10/raml-10.md/#resource-types-and-traits
+
+package raml
+
+// A resource type is a partial resource definition that can be applied to resources.
+// Its properties can use parameters, ex: `<<item>>`, and the reserved parameters
+// `<<resourcePath>>` and `<<resourcePathName>>`.
+type ResourceType struct {
+
+	// Instructions on how and when the resource type should be used.
+	Usage string `yaml:"usage,omitempty"`
+
+	// A substantial, human-friendly description of the resources of this type.
+	Description string `yaml:"description,omitempty"`
+
+	// The methods of the resources of this type, optional so that
+	// a resource applying the type doesn't have to implement them all.
+	Get     *Method `yaml:"get?,omitempty"`
+	Patch   *Method `yaml:"patch?,omitempty"`
+	Put     *Method `yaml:"put?,omitempty"`
+	Head    *Method `yaml:"head?,omitempty"`
+	Post    *Method `yaml:"post?,omitempty"`
+	Delete  *Method `yaml:"delete?,omitempty"`
+	Options *Method `yaml:"options?,omitempty"`
+}
@@ -25,5 +25,5 @@ type Response struct {
 	//////// Headers map[string]Header `yaml:"headers,omitempty"`
 
 	// The body of the response
-	Body Body `yaml:"body"`
+	Body Body `yaml:"body,omitempty"`
 }
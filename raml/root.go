@@ -47,7 +47,7 @@ type Root struct {
 	// The baseUriParameters node has the same structure and semantics as
 	// the uriParameters node on a resource node, except that it specifies parameters
 	// in the base URI rather than the relative URI of a resource.
-	BaseURIParameters map[string]Parameter `yaml:"baseUriParameters,omitempty"`
+	BaseURIParameters map[string]Type `yaml:"baseUriParameters,omitempty"`
 
 	// The protocols supported by the API.
 	// The OPTIONAL protocols property specifies the protocols that an API supports.
@@ -91,8 +91,7 @@ type Root struct {
 	Traits map[string]Trait `yaml:"traits,omitempty"`
 
 	// Declarations of resource types for use within the API.
-	// TODO
-	//////// ResourceTypes map[string]interface{} `yaml:"resourceTypes,omitempty"`
+	ResourceTypes map[string]ResourceType `yaml:"resourceTypes,omitempty"`
 
 	// Declarations of annotation types for use by annotations.
 	// The value of the annotationsType node is a map whose keys define annotation type names,
@@ -110,7 +109,8 @@ type Root struct {
 	SecuritySchemes map[string]SecurityScheme `yaml:"securitySchemes,omitempty"`
 
 	// The security schemes that apply to every resource and method in the API.
-	SecuredBy []string `yaml:"securedBy,flow,omitempty"`
+	// The value is the name of a security scheme, a `SecuredBy` or nil for no security.
+	SecuredBy []interface{} `yaml:"securedBy,flow,omitempty"`
 
 	// Imported external libraries for use within the API.
 	// TODO
@@ -51,5 +51,38 @@ type SecurityScheme struct {
 	DescribedBy SecuritySchemeDescription `yaml:"describedBy,omitempty"`
 
 	// The settings attribute MAY be used to provide security scheme-specific information.
-	Settings string `yaml:"settings,omitempty"`
+	Settings *SecuritySchemeSettings `yaml:"settings,omitempty"`
+}
+
+// Settings of the OAuth 1.0 and OAuth 2.0 security schemes
+type SecuritySchemeSettings struct {
+	// OAuth 1.0: the URI of the Temporary Credential Request endpoint as defined in RFC5849 Section 2.1
+	RequestTokenUri string `yaml:"requestTokenUri,omitempty"`
+
+	// The URI of the Resource Owner Authorization endpoint,
+	// as defined in RFC5849 Section 2.2 for OAuth 1.0 and RFC6749 Section 3.1 for OAuth 2.0
+	AuthorizationUri string `yaml:"authorizationUri,omitempty"`
+
+	// OAuth 1.0: the URI of the Token Request endpoint as defined in RFC5849 Section 2.3
+	TokenCredentialsUri string `yaml:"tokenCredentialsUri,omitempty"`
+
+	// OAuth 1.0: a list of the signature methods used by the server: HMAC-SHA1, RSA-SHA1 or PLAINTEXT
+	Signatures []string `yaml:"signatures,omitempty"`
+
+	// OAuth 2.0: the URI of the Token Endpoint as defined in RFC6749 Section 3.2
+	AccessTokenUri string `yaml:"accessTokenUri,omitempty"`
+
+	// OAuth 2.0: a list of the authorization grants supported by the API:
+	// authorization_code, password, client_credentials, implicit, or any absolute URI
+	AuthorizationGrants []string `yaml:"authorizationGrants,omitempty"`
+
+	// OAuth 2.0: a list of scopes supported by the security scheme as defined in RFC6749 Section 3.3
+	Scopes []string `yaml:"scopes,omitempty"`
+}
+
+// A security scheme applied with parameters, ex: the scopes of an OAuth 2.0 scheme
+type SecuredBy map[string]SecuredByParameters
+
+type SecuredByParameters struct {
+	Scopes []string `yaml:"scopes,flow,omitempty"`
 }
@@ -117,7 +117,7 @@ type ArrayType struct {
 
 	// Indicates the type all items in the array are inherited from.
 	// Can be a reference to an existing type or an inline type declaration.
-	Items string `yaml:"items,omitempty"`
+	Items interface{} `yaml:"items,omitempty"`
 
 	// Minimum amount of items in array. Value MUST be equal to or greater than 0.
 	MinItems int64 `yaml:"minItems,omitempty"`
@@ -5,7 +5,9 @@ package godoc2api
 import (
 	"bufio"
 	"fmt"
+	"net/http"
 	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"runtime"
@@ -17,23 +19,93 @@ const (
 	_HANDLER_SIGNATURE  = "http.HandlerFunc"
 )
 
+// A HandlerResolver finds the function that documents a handler.
+// It returns the entry point of this function, or false if the handler
+// is not supported by the resolver.
+type HandlerResolver func(handler interface{}) (pc uintptr, ok bool)
+
+// Registry of the handler resolvers
+var index_resolvers []HandlerResolver
+
+// Registry of the signatures of the functions accepted as handlers
+var index_signatures = []string{_CALLBACK_SIGNATURE, _HANDLER_SIGNATURE}
+
 // Store all the comment strings describing a callback
 // identified by by its file path and line number
 var index_comment map[string]map[int]string
 
+// Register a new handler resolver. The resolvers are tried
+// in their order of registration, before the default ones.
+func RegisterHandlerResolver(r HandlerResolver) {
+	index_resolvers = append(index_resolvers, r)
+}
+
+// Accept the functions of a given signature as handlers.
+//
+// Example
+//
+// To document the handlers of a framework like gin or echo
+//	RegisterHandlerSignature("func(*gin.Context)")
+//	RegisterHandlerSignature("func(echo.Context) error")
+func RegisterHandlerSignature(signature string) {
+	index_signatures = append(index_signatures, signature)
+}
+
+// Resolve the functions which signature is registered
+func resolveHandlerFunc(handler interface{}) (uintptr, bool) {
+	v := reflect.ValueOf(handler)
+	if v.Kind() != reflect.Func {
+		return 0, false
+	}
+	for _, signature := range index_signatures {
+		if v.Type().String() == signature {
+			return v.Pointer(), true
+		}
+	}
+	return 0, false
+}
+
+// Resolve the implementations of `http.Handler` to their method `ServeHTTP`
+func resolveHTTPHandler(handler interface{}) (uintptr, bool) {
+	if _, ok := handler.(http.Handler); !ok {
+		return 0, false
+	}
+	m, ok := reflect.TypeOf(handler).MethodByName("ServeHTTP")
+	if !ok {
+		return 0, false
+	}
+	return m.Func.Pointer(), true
+}
+
+// Find the function that documents a handler
+func resolveHandler(handler interface{}) (uintptr, bool) {
+	if handler == nil {
+		return 0, false
+	}
+	resolvers := append([]HandlerResolver{}, index_resolvers...)
+	resolvers = append(resolvers, resolveHandlerFunc, resolveHTTPHandler)
+	for _, resolve := range resolvers {
+		if pc, ok := resolve(handler); ok {
+			return pc, true
+		}
+	}
+	return 0, false
+}
+
 // Analyse a callback or a struct describing a route
-// and extract its comments, and eventually extra keywords
-func readComment(user_route interface{}) (c string, extra map[string]interface{}, err error) {
+// and extract its comments, eventually extra keywords,
+// and the path of the package declaring the callback
+func readComment(user_route interface{}) (c string, extra map[string]interface{}, pkg_path string, err error) {
 
 	var callback uintptr
 	extra = map[string]interface{}{}
 
 	v := reflect.ValueOf(user_route)
-	if v.Type().String() == _CALLBACK_SIGNATURE || v.Type().String() == _HANDLER_SIGNATURE {
+	if pc, ok := resolveHandler(user_route); ok {
 		// If the input is a callback
 		// go directly to fetching the comment
-		callback = v.Pointer()
-	} else {
+		callback = pc
+	} else if v.Kind() == reflect.Struct {
 		// If the input is a struct, read its fields
 		// to find out about the callback and if possible,
 		// about extra keywords
@@ -41,11 +113,17 @@ func readComment(user_route interface{}) (c string, extra map[string]interface{}
 			f := v.Field(i)
 			tf := v.Type().Field(i)
 			tag := tf.Tag.Get(_MAIN_TAG_NAME)
-			if tag == "" {
+			if tag == "" || !f.CanInterface() {
 				continue
 			}
-			if tag == TAG_HANDLER && (f.Type().String() == _CALLBACK_SIGNATURE || f.Type().String() == _HANDLER_SIGNATURE) {
-				callback = f.Pointer()
+			if tag == TAG_HANDLER {
+				if pc, ok := resolveHandler(f.Interface()); ok {
+					callback = pc
+				}
+			} else if tag == TAG_REQUEST && (tf.Type.Kind() == reflect.Struct || tf.Type.Kind() == reflect.Ptr || tf.Type.Kind() == reflect.Interface) {
+				if tf.Type.Kind() == reflect.Struct || !f.IsNil() {
+					extra[tag] = f.Interface()
+				}
 			} else if tf.Type.Kind() == reflect.String {
 				extra[tag] = f.String()
 			} else if tf.Type.Kind() == reflect.Bool {
@@ -57,17 +135,34 @@ func readComment(user_route interface{}) (c string, extra map[string]interface{}
 	}
 
 	if callback == 0 {
-		return "", nil, fmt.Errorf("no callback found")
+		return "", nil, "", fmt.Errorf("no callback found")
 	}
 
 	// Extract the comment of the callback
-	file, line := runtime.FuncForPC(callback).FileLine(callback)
-	c, err = readCommentFromFile(file, line)
+	fn := runtime.FuncForPC(callback)
+	file, line := fn.FileLine(callback)
+	pkg_path, _, _ = splitFuncName(fn.Name())
+	if _, stat_err := os.Stat(file); stat_err == nil {
+		storeSourceDir(pkg_path, filepath.Dir(file))
+		c, err = readCommentFromFile(file, line)
+		if err != nil {
+			return "", nil, "", err
+		}
+		if c == "" {
+			// The callback may be a closure, use the comment of its factory
+			c = sourceFuncCommentAt(file, line)
+		}
+	}
+	if c == "" {
+		// The callback may be a wrapper generated by the compiler,
+		// ex: a method value, use the comment of the method or of its receiver
+		c = sourceFuncComment(fn.Name())
+	}
 
 	// Alert if there is no comment but continue, maybe it has
 	// been defined through the extra keywords
 	if c == "" {
-		warn("the handler doesn't have any comment or its content is empty: %v\n", runtime.FuncForPC(callback).Name())
+		warn("the handler doesn't have any comment or its content is empty: %v\n", fn.Name())
 	}
 
 	return
@@ -0,0 +1,30 @@
+package godoc2api
+
+import "testing"
+
+// Context of a web framework
+type testContext struct{}
+
+// Create an item
+// @resource POST /items
+// @body {string}
+// @response {string}
+func testFrameworkHandler(c *testContext) error {
+	return nil
+}
+
+func TestRegisterHandlerSignature(t *testing.T) {
+	defer func(signatures []string) { index_signatures = signatures }(index_signatures)
+
+	d := Documentation{}
+	if err := d.AddRoute(testFrameworkHandler); err == nil {
+		t.Errorf("expected an error for the signature not registered")
+	}
+	RegisterHandlerSignature("func(*godoc2api.testContext) error")
+	if err := d.AddRoute(testFrameworkHandler); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if _, ok := d.routes["POST /items"]; !ok {
+		t.Errorf("expected the route POST /items, got %v", d.routes)
+	}
+}
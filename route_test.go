@@ -0,0 +1,45 @@
+package godoc2api
+
+import (
+	"testing"
+
+	"github.com/florenthobein/godoc2api/raml"
+)
+
+func TestGroupUnion(t *testing.T) {
+	for s, expected := range map[string]string{
+		"string":           "string",
+		"string[]":         "string[]",
+		"string | nil":     "(string | nil)",
+		"(string | nil)":   "(string | nil)",
+		"(string | nil)[]": "(string | nil)[]",
+		"(string) | (nil)": "((string) | (nil))",
+	} {
+		if res := groupUnion(s); res != expected {
+			t.Errorf("expected %s for %s, got %s", expected, s, res)
+		}
+	}
+}
+
+func TestResourceTypeUnionParameter(t *testing.T) {
+	r := Route{ResourceParams: map[string]string{"item": "string | nil"}}
+	if !r.isResourceTypeType("<<item>>[]", &raml.Type{Type: Type("(string | nil)[]")}) {
+		t.Errorf("expected the array of the union to match")
+	}
+	if r.isResourceTypeType("<<item>>[]", &raml.Type{Type: Type("string | nil[]")}) {
+		t.Errorf("expected the union of an array not to match")
+	}
+}
+
+func TestResponseMediaTypeWithoutType(t *testing.T) {
+	r := Route{}
+	if err := r.addTag(TAG_RESPONSE, "200 text/csv The report"); err == nil {
+		t.Errorf("expected an error for the media type without type")
+	}
+	if err := r.addTag(TAG_RESPONSE, "204 No content"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if resp := r.Responses[204]; resp.Description != "No content" || len(resp.Body) != 0 {
+		t.Errorf("expected a response without body, got %v", resp)
+	}
+}
@@ -0,0 +1,121 @@
+// The scalar is responsible for the go types that are rendered
+// as RAML scalar types, like `time.Time` as `datetime`
+
+package godoc2api
+
+import (
+	"reflect"
+
+	"github.com/florenthobein/godoc2api/raml"
+)
+
+// Registry of the scalar types, identified by the go name of the types,
+// qualified by the path of their package.
+// The types encoded as objects, like `url.URL` or `sql.NullString`,
+// are not scalars and are rendered with their fields.
+var index_scalars = map[string]raml.Type{
+	"bool":    {Type: "boolean"},
+	"string":  {Type: "string"},
+	"int":     {Type: "integer"},
+	"int8":    {Type: "integer", NumberType: raml.NumberType{Format: "int8"}},
+	"int16":   {Type: "integer", NumberType: raml.NumberType{Format: "int16"}},
+	"int32":   {Type: "integer", NumberType: raml.NumberType{Format: "int32"}},
+	"int64":   {Type: "integer", NumberType: raml.NumberType{Format: "int64"}},
+	"uint":    {Type: "integer", NumberType: raml.NumberType{Minimum: floatPtr(0)}},
+	"uint8":   {Type: "integer", NumberType: raml.NumberType{Minimum: floatPtr(0), Maximum: floatPtr(255)}},
+	"uint16":  {Type: "integer", NumberType: raml.NumberType{Minimum: floatPtr(0), Maximum: floatPtr(65535)}},
+	"uint32":  {Type: "integer", NumberType: raml.NumberType{Minimum: floatPtr(0)}},
+	"uint64":  {Type: "integer", NumberType: raml.NumberType{Minimum: floatPtr(0)}},
+	"float32": {Type: "number", NumberType: raml.NumberType{Format: "float"}},
+	"float64": {Type: "number", NumberType: raml.NumberType{Format: "double"}},
+
+	// Standard library
+	"time.Time":                    {Type: "datetime", NumberType: raml.NumberType{Format: "rfc3339"}},
+	"time.Duration":                {Type: "integer", Description: "Duration in nanoseconds", NumberType: raml.NumberType{Format: "int64"}},
+	"mime/multipart.FileHeader":    {Type: "file"},
+	"[]uint8":                      {Type: "string", Description: "Base64 encoded"},
+	"[]byte":                       {Type: "string", Description: "Base64 encoded"},
+	"encoding/json.RawMessage":     {Type: "any"},
+	"encoding/json/jsontext.Value": {Type: "any"}, // json.RawMessage with the json v2 experiment
+	"net.IP":                       {Type: "string"},
+	"math/big.Int":                 {Type: "integer"},
+	"math/big.Float":               {Type: "string", Description: "Decimal number"}, // encoded by MarshalText
+	"math/big.Rat":                 {Type: "string", StringType: raml.StringType{Pattern: strPtr(`^-?[0-9]+(/[0-9]+)?$`)}},
+}
+
+// Configure a go type to be rendered as a RAML scalar type, with its facets,
+// for the value types that are encoded as scalars.
+//
+// Example
+//
+//	DefineScalar(decimal.Decimal{}, raml.Type{
+//		Type:       "number",
+//		NumberType: raml.NumberType{Format: "double"},
+//	})
+func DefineScalar(obj interface{}, t raml.Type) {
+	rt := reflect.TypeOf(obj)
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if _, ok := t.Type.(string); !ok {
+		warn("can't define scalar `%s`: its type should be a string", goTypeName(rt))
+		return
+	}
+	index_scalars[goTypeName(rt)] = t
+}
+
+// Check if a go type is rendered as a scalar, and return the name of its RAML type
+func isScalar(name string) (bool, string) {
+	if t, ok := index_scalars[name]; ok {
+		return true, t.Type.(string)
+	}
+	return false, name
+}
+
+// Apply the facets of the scalar a go type is rendered as, ex: `format: int64`
+func applyScalarFacets(type_name string, t *raml.Type) {
+	// Follow the aliases, ex: `type ID int64`
+	e, err := parseTypeExpr(type_name)
+	for i := 0; err == nil && i < 10; i++ {
+		for e.kind == _EXPR_POINTER {
+			e = e.items[0]
+		}
+		alias, ok := isTypeAlias(e.String())
+		if !ok || alias == e.String() {
+			break
+		}
+		e, err = parseTypeExpr(alias)
+	}
+	if err != nil {
+		return
+	}
+	s, ok := index_scalars[e.String()]
+	if !ok {
+		return
+	}
+	t.NumberType = s.NumberType
+	t.StringType = s.StringType
+	t.DateType = s.DateType
+	t.FileType = s.FileType
+	if t.Description == "" {
+		t.Description = s.Description
+	}
+}
+
+// Kind of the values of a RAML scalar type
+func scalarKind(raml_type string) reflect.Kind {
+	switch raml_type {
+	case "string":
+		return reflect.String
+	case "integer":
+		return reflect.Int64
+	case "number":
+		return reflect.Float64
+	case "boolean":
+		return reflect.Bool
+	}
+	return reflect.Struct
+}
+
+func floatPtr(f float64) *float64 { return &f }
+func strPtr(s string) *string     { return &s }
@@ -0,0 +1,587 @@
+// The source is responsible for the static analysis of the packages
+// that are documented, when reading them line by line is not enough
+
+package godoc2api
+
+import (
+	"go/ast"
+	"go/build"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Parsed files, identified by their path
+var index_source_files map[string]*ast.File
+
+// Directories of the packages already met, identified by their import path
+var index_source_dirs map[string]string
+
+// File set shared by all the parsed files
+var source_fset = token.NewFileSet()
+
+// Parse a file and store it
+func sourceFile(file string) (*ast.File, error) {
+	if index_source_files == nil {
+		index_source_files = map[string]*ast.File{}
+	}
+	if f, ok := index_source_files[file]; ok {
+		return f, nil
+	}
+	f, err := parser.ParseFile(source_fset, file, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	index_source_files[file] = f
+	return f, nil
+}
+
+// Remember the directory of a package
+func storeSourceDir(pkg_path, dir string) {
+	if pkg_path == "" || dir == "" {
+		return
+	}
+	if index_source_dirs == nil {
+		index_source_dirs = map[string]string{}
+	}
+	index_source_dirs[pkg_path] = dir
+}
+
+// Find the directory of a package
+func sourceDir(pkg_path string) (string, bool) {
+	if dir, ok := index_source_dirs[pkg_path]; ok {
+		return dir, true
+	}
+	// External test packages live with the package they test
+	path := strings.TrimSuffix(pkg_path, "_test")
+	if dir, ok := index_source_dirs[path]; ok {
+		return dir, true
+	}
+	if path == "main" {
+		return "", false
+	}
+	p, err := build.Import(path, "", build.FindOnly)
+	if err != nil || p.Dir == "" {
+		debug("can't find the sources of %s: %v", pkg_path, err)
+		return "", false
+	}
+	storeSourceDir(path, p.Dir)
+	return p.Dir, true
+}
+
+// Parse all the files of a package, tests included
+func sourcePackage(pkg_path string) (files []*ast.File) {
+	dir, ok := sourceDir(pkg_path)
+	if !ok {
+		return nil
+	}
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".go" {
+			continue
+		}
+		f, err := sourceFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			debug("can't parse %s: %v", e.Name(), err)
+			continue
+		}
+		files = append(files, f)
+	}
+	return
+}
+
+// Text of a comment group, as written in the file
+func sourceComment(cg *ast.CommentGroup) string {
+	if cg == nil {
+		return ""
+	}
+	c := ""
+	for _, l := range cg.List {
+		c += l.Text + "\n"
+	}
+	return c
+}
+
+// Comment of the function declared around a line,
+// ex: the factory that returns a closure
+func sourceFuncCommentAt(file string, line int) string {
+	f, err := sourceFile(file)
+	if err != nil {
+		return ""
+	}
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		if source_fset.Position(fd.Pos()).Line <= line && line <= source_fset.Position(fd.End()).Line {
+			return sourceComment(fd.Doc)
+		}
+	}
+	return ""
+}
+
+// Split the name of a runtime function into its package path, receiver and name,
+// ex: `github.com/me/api.(*Service).GetBook-fm` => `github.com/me/api`, `Service`, `GetBook`
+func splitFuncName(full string) (pkg_path, recv, name string) {
+	i := strings.LastIndex(full, "/")
+	j := strings.Index(full[i+1:], ".")
+	if j < 0 {
+		return "", "", full
+	}
+	pkg_path, name = full[:i+1+j], full[i+1+j+1:]
+
+	// Method values and closures are wrapped
+	name = strings.TrimSuffix(name, "-fm")
+	name = regexp.MustCompile(`(\.(func|gowrap|deferwrap)\d+)+$`).ReplaceAllString(name, "")
+
+	if k := strings.LastIndex(name, "."); k >= 0 {
+		recv = strings.Trim(name[:k], "(*)")
+		name = name[k+1:]
+	}
+	return
+}
+
+// Comment of a function or a method identified by its runtime name.
+// If the method doesn't have any comment, the one of its receiver is used.
+func sourceFuncComment(full string) string {
+	pkg_path, recv, name := splitFuncName(full)
+	files := sourcePackage(pkg_path)
+	type_comment := ""
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Name.Name != name || receiverName(d) != recv {
+					continue
+				}
+				if c := sourceComment(d.Doc); c != "" {
+					return c
+				}
+			case *ast.GenDecl:
+				if recv == "" || d.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range d.Specs {
+					if ts := spec.(*ast.TypeSpec); ts.Name.Name == recv {
+						type_comment = sourceComment(ts.Doc)
+						if type_comment == "" {
+							type_comment = sourceComment(d.Doc)
+						}
+					}
+				}
+			}
+		}
+	}
+	return type_comment
+}
+
+// Name of the receiver of a method, without pointer nor type parameters
+func receiverName(fd *ast.FuncDecl) string {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return ""
+	}
+	return typeExprName(fd.Recv.List[0].Type)
+}
+
+// Name of the type used in an expression, without package, pointer nor type parameters
+func typeExprName(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.SelectorExpr:
+			expr = e.Sel
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// Find the declaration of a type, and its comment
+func sourceTypeSpec(pkg_path, name string) (*ast.TypeSpec, *ast.CommentGroup) {
+	_, ts, doc := sourceTypeDecl(pkg_path, name)
+	return ts, doc
+}
+
+// Find the declaration of a type, the file declaring it and its comment
+func sourceTypeDecl(pkg_path, name string) (*ast.File, *ast.TypeSpec, *ast.CommentGroup) {
+	// Instances of generic types are declared without their arguments
+	name = strings.SplitN(name, "[", 2)[0]
+	if pkg_path == "" || name == "" {
+		return nil, nil, nil
+	}
+	for _, f := range sourcePackage(pkg_path) {
+		for _, decl := range f.Decls {
+			d, ok := decl.(*ast.GenDecl)
+			if !ok || d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				if ts := spec.(*ast.TypeSpec); ts.Name.Name == name {
+					if ts.Doc == nil && len(d.Specs) == 1 {
+						return f, ts, d.Doc
+					}
+					return f, ts, ts.Doc
+				}
+			}
+		}
+	}
+	return nil, nil, nil
+}
+
+// Name of a package, as used to qualify its types, ex: `models` for `github.com/me/api/models`
+func sourcePackageName(pkg_path string) string {
+	name := ""
+	for _, f := range sourcePackage(pkg_path) {
+		// External test packages share the directory of the package they test
+		if strings.HasSuffix(f.Name.Name, "_test") == strings.HasSuffix(pkg_path, "_test") {
+			return f.Name.Name
+		}
+		if name == "" {
+			name = f.Name.Name
+		}
+	}
+	return name
+}
+
+// Path of the package imported under a name in a file, or in any file of the package
+func sourceImportPath(pkg_path string, file *ast.File, name string) string {
+	files := []*ast.File{file}
+	if file == nil {
+		files = sourcePackage(pkg_path)
+	}
+	for _, f := range files {
+		for _, imp := range f.Imports {
+			path, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				continue
+			}
+			if imp.Name != nil {
+				if imp.Name.Name == name {
+					return path
+				}
+				continue
+			}
+			if path[strings.LastIndex(path, "/")+1:] == name || sourcePackageName(path) == name {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// Kinds of the predeclared types
+var source_builtin_kinds = map[string]reflect.Kind{
+	"bool":        reflect.Bool,
+	"string":      reflect.String,
+	"int":         reflect.Int,
+	"int8":        reflect.Int8,
+	"int16":       reflect.Int16,
+	"int32":       reflect.Int32,
+	"rune":        reflect.Int32,
+	"int64":       reflect.Int64,
+	"uint":        reflect.Uint,
+	"uint8":       reflect.Uint8,
+	"byte":        reflect.Uint8,
+	"uint16":      reflect.Uint16,
+	"uint32":      reflect.Uint32,
+	"uint64":      reflect.Uint64,
+	"uintptr":     reflect.Uintptr,
+	"float32":     reflect.Float32,
+	"float64":     reflect.Float64,
+	"complex64":   reflect.Complex64,
+	"complex128":  reflect.Complex128,
+	"any":         reflect.Interface,
+	"error":       reflect.Interface,
+	"interface{}": reflect.Interface,
+}
+
+// Package and name of the type used in an expression, pointers excluded,
+// ex: `*models.Book` => `github.com/me/api/models`, `Book`
+func sourceTypeRef(pkg_path string, file *ast.File, expr ast.Expr) (string, string) {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.Ident:
+			if _, ok := source_builtin_kinds[e.Name]; ok {
+				return "", e.Name
+			}
+			return pkg_path, e.Name
+		case *ast.SelectorExpr:
+			if x, ok := e.X.(*ast.Ident); ok {
+				return sourceImportPath(pkg_path, file, x.Name), e.Sel.Name
+			}
+			return "", ""
+		default:
+			return "", ""
+		}
+	}
+}
+
+// Kind of the type used in an expression, pointers excluded
+func sourceKind(pkg_path string, file *ast.File, expr ast.Expr) reflect.Kind {
+	visited := map[string]bool{}
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.ArrayType:
+			if e.Len == nil {
+				return reflect.Slice
+			}
+			return reflect.Array
+		case *ast.MapType:
+			return reflect.Map
+		case *ast.StructType:
+			return reflect.Struct
+		case *ast.InterfaceType:
+			return reflect.Interface
+		case *ast.FuncType:
+			return reflect.Func
+		case *ast.ChanType:
+			return reflect.Chan
+		case *ast.Ident, *ast.SelectorExpr:
+			path, name := sourceTypeRef(pkg_path, file, e)
+			if path == "" {
+				return source_builtin_kinds[name]
+			}
+			if is_scalar, raml_type := isScalar(path + "." + name); is_scalar {
+				// ex: time.Time
+				return scalarKind(raml_type)
+			}
+			if visited[path+"."+name] {
+				return reflect.Invalid
+			}
+			visited[path+"."+name] = true
+			f, ts, _ := sourceTypeDecl(path, name)
+			if ts == nil {
+				return reflect.Invalid
+			}
+			pkg_path, file, expr = path, f, ts.Type
+		default:
+			return reflect.Invalid
+		}
+	}
+}
+
+// Go type used in an expression, written like `reflect` would,
+// ex: `[]*Book` => `[]*models.Book`.
+// The named types met are discovered, the unknown ones are considered as `interface{}`.
+func sourceTypeName(pkg_path string, file *ast.File, expr ast.Expr) string {
+	return sourceTypeNameOf(pkg_path, file, expr, nil)
+}
+
+// Same as `sourceTypeName`, the type parameters being replaced by their arguments,
+// ex: `[]T` => `[]models.Book`
+func sourceTypeNameOf(pkg_path string, file *ast.File, expr ast.Expr, type_args map[string]string) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return "*" + sourceTypeNameOf(pkg_path, file, e.X, type_args)
+	case *ast.ParenExpr:
+		return sourceTypeNameOf(pkg_path, file, e.X, type_args)
+	case *ast.ArrayType:
+		return "[]" + sourceTypeNameOf(pkg_path, file, e.Elt, type_args)
+	case *ast.MapType:
+		return "map[" + sourceTypeNameOf(pkg_path, file, e.Key, type_args) + "]" + sourceTypeNameOf(pkg_path, file, e.Value, type_args)
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		// Instance of a generic type, ex: Page[T]
+		var x ast.Expr
+		var indices []ast.Expr
+		if ie, ok := e.(*ast.IndexExpr); ok {
+			x, indices = ie.X, []ast.Expr{ie.Index}
+		} else {
+			ile := e.(*ast.IndexListExpr)
+			x, indices = ile.X, ile.Indices
+		}
+		base := sourceTypeNameOf(pkg_path, file, x, type_args)
+		if base == "interface{}" {
+			return base
+		}
+		args := []string{}
+		for _, index := range indices {
+			args = append(args, sourceTypeNameOf(pkg_path, file, index, type_args))
+		}
+		return base + "[" + strings.Join(args, ",") + "]"
+	case *ast.Ident, *ast.SelectorExpr:
+		if id, ok := e.(*ast.Ident); ok {
+			if arg, ok := type_args[id.Name]; ok {
+				return arg
+			}
+		}
+		path, name := sourceTypeRef(pkg_path, file, e)
+		if path == "" {
+			switch source_builtin_kinds[name] {
+			case reflect.Invalid:
+				return "interface{}"
+			case reflect.Interface:
+				return "interface{}"
+			}
+			return source_builtin_kinds[name].String()
+		}
+		full := path + "." + name
+		if is_scalar, _ := isScalar(full); is_scalar {
+			// ex: time.Time
+			return full
+		}
+		if ref, ok := discoverSourceType(path, name); ok {
+			return ref
+		}
+	}
+	return "interface{}"
+}
+
+// Comments of the fields of a struct, identified by the name of the fields
+func sourceFieldComments(pkg_path, name string) map[string]string {
+	res := map[string]string{}
+	ts, _ := sourceTypeSpec(pkg_path, name)
+	if ts == nil {
+		return res
+	}
+	st, ok := ts.Type.(*ast.StructType)
+	if !ok {
+		return res
+	}
+	for _, field := range st.Fields.List {
+		c := field.Doc.Text()
+		if c == "" {
+			c = field.Comment.Text()
+		}
+		c = strings.Join(strings.Fields(c), " ")
+		if c == "" {
+			continue
+		}
+		for _, n := range field.Names {
+			res[n.Name] = c
+		}
+		if len(field.Names) == 0 {
+			// Embedded field
+			res[typeExprName(field.Type)] = c
+		}
+	}
+	return res
+}
+
+// Values of the constants declared with a type, and their comments,
+// ex: `const ( StatusPending OrderStatus = "pending" )` => ["pending"]
+func sourceEnumValues(pkg_path, name string) (values []interface{}, comments []string) {
+	for _, f := range sourcePackage(pkg_path) {
+		for _, decl := range f.Decls {
+			d, ok := decl.(*ast.GenDecl)
+			if !ok || d.Tok != token.CONST {
+				continue
+			}
+			// Values are repeated when omitted in a block
+			typ, exprs := "", []ast.Expr{}
+			for iota, spec := range d.Specs {
+				vs := spec.(*ast.ValueSpec)
+				if vs.Type != nil {
+					typ, exprs = typeExprName(vs.Type), vs.Values
+				} else if len(vs.Values) > 0 {
+					typ, exprs = "", vs.Values
+					// Conversions, ex: OrderStatus("pending")
+					if call, ok := vs.Values[0].(*ast.CallExpr); ok && len(call.Args) == 1 {
+						typ = typeExprName(call.Fun)
+					}
+				}
+				if typ != name {
+					continue
+				}
+				for i, n := range vs.Names {
+					if n.Name == "_" || i >= len(exprs) {
+						continue
+					}
+					v := sourceConstant(exprs[i], iota)
+					if v == nil {
+						continue
+					}
+					c := vs.Doc.Text()
+					if c == "" {
+						c = vs.Comment.Text()
+					}
+					values = append(values, v)
+					comments = append(comments, strings.Join(strings.Fields(c), " "))
+				}
+			}
+		}
+	}
+	return
+}
+
+// Evaluate a constant expression
+func sourceConstant(expr ast.Expr, iota int) (res interface{}) {
+	// Operations on values of different kinds are not supported
+	defer func() {
+		if recover() != nil {
+			res = nil
+		}
+	}()
+	var eval func(expr ast.Expr) constant.Value
+	eval = func(expr ast.Expr) constant.Value {
+		switch e := expr.(type) {
+		case *ast.BasicLit:
+			return constant.MakeFromLiteral(e.Value, e.Kind, 0)
+		case *ast.Ident:
+			if e.Name == "iota" {
+				return constant.MakeInt64(int64(iota))
+			}
+		case *ast.ParenExpr:
+			return eval(e.X)
+		case *ast.CallExpr:
+			if len(e.Args) == 1 {
+				return eval(e.Args[0])
+			}
+		case *ast.UnaryExpr:
+			return constant.UnaryOp(e.Op, eval(e.X), 0)
+		case *ast.BinaryExpr:
+			x, y := eval(e.X), eval(e.Y)
+			if e.Op == token.SHL || e.Op == token.SHR {
+				s, ok := constant.Uint64Val(y)
+				if !ok {
+					return constant.MakeUnknown()
+				}
+				return constant.Shift(x, e.Op, uint(s))
+			}
+			if x.Kind() == constant.Unknown || y.Kind() == constant.Unknown {
+				return constant.MakeUnknown()
+			}
+			return constant.BinaryOp(x, e.Op, y)
+		}
+		return constant.MakeUnknown()
+	}
+	v := eval(expr)
+	switch v.Kind() {
+	case constant.String:
+		return constant.StringVal(v)
+	case constant.Int:
+		if i, ok := constant.Int64Val(v); ok {
+			return i
+		}
+	case constant.Float:
+		f, _ := constant.Float64Val(v)
+		return f
+	case constant.Bool:
+		return constant.BoolVal(v)
+	}
+	return nil
+}
@@ -19,6 +19,12 @@ const (
 	TAG_EXAMPLE     = "example"     // eventual example describing the use of the route ([]string)
 	TAG_EXAMPLES    = "examples"    // eventual examples describing the use of the route ([][]string)
 	TAG_RESPONSE    = "response"    // response type (string or []string)
+	TAG_REQUEST     = "request"     // request struct describing the parameters and the body (struct, string or []string)
+	TAG_PUBLIC      = "public"      // route without security, whatever the securities applied by default (bool)
+
+	TAG_RESOURCE_TYPE        = "resourceType"        // resource type of the resource, with its parameters, ex: collection item={Book} (string or []string)
+	TAG_RESOURCE_DESCRIPTION = "resourceDescription" // description of the resource, eventually preceded by its display name (string or []string)
+	TAG_PROTOCOLS            = "protocols"           // protocols of the route, overriding the ones of the API, ex: HTTPS (string or []string)
 )
 
 // Tag types
@@ -39,7 +45,12 @@ const _RESERVED_TAGS = `(` +
 	TAG_QUERY + `|` +
 	TAG_BODY + `|` +
 	TAG_EXAMPLE + `|` +
-	TAG_RESPONSE + `)`
+	TAG_RESPONSE + `|` +
+	TAG_REQUEST + `|` +
+	TAG_PUBLIC + `|` +
+	TAG_RESOURCE_TYPE + `|` +
+	TAG_RESOURCE_DESCRIPTION + `|` +
+	TAG_PROTOCOLS + `)`
 
 // Registry of tags
 var index_tag map[string]uint
@@ -0,0 +1,8 @@
+// Package used to test the types declared in other packages
+package auth
+
+// An error of the authentication
+type Error struct {
+	Code   string `json:"code"`
+	Reason string `json:"reason"` // Why the user was rejected
+}
@@ -0,0 +1,75 @@
+package godoc2api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/florenthobein/godoc2api"
+)
+
+func TestWithBaseURIParameters(t *testing.T) {
+	output_dir := "test26"
+	defer finalize(output_dir, t)
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "https://{region}.mywebsite/{version}",
+		BaseURIParameters: map[string]godoc2api.Parameter{
+			"region": {
+				Description: "Region of the servers",
+				Enum:        []interface{}{"eu", "us"},
+				Default:     "eu",
+			},
+		},
+	}
+
+	for _, handler := range []interface{}{PingHandler, LegacyPingHandler} {
+		err := doc.AddRoute(handler)
+		if err != nil {
+			t.Errorf(err.Error())
+			return
+		}
+	}
+
+	err := doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+// Check the API
+// @resource GET /ping
+func PingHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+// Check the API, also in clear
+// @resource GET /legacy/ping
+// @protocols http, https
+func LegacyPingHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+func TestWithUnknownProtocol(t *testing.T) {
+	doc := godoc2api.Documentation{
+		Title:   "Test API",
+		Version: "v1",
+		URL:     "ws://mywebsite/{version}",
+	}
+	err := doc.AddRoute(PingHandler)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+	if err := doc.Print(); err != nil {
+		t.Errorf("unexpected error for the protocol of the URL: %v", err)
+	}
+
+	doc.Protocols = []string{"ws"}
+	if err := doc.Print(); err == nil {
+		t.Errorf("expected an error for the protocol defined")
+	}
+}
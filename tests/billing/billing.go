@@ -0,0 +1,8 @@
+// Package used to test the types declared in other packages
+package billing
+
+// An error of the billing
+type Error struct {
+	Code    string `json:"code"`
+	Invoice string `json:"invoice,omitempty"` // Invoice concerned
+}
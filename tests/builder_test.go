@@ -0,0 +1,53 @@
+package godoc2api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/florenthobein/godoc2api"
+)
+
+func TestWithBuilders(t *testing.T) {
+	output_dir := "test20"
+	defer finalize(output_dir, t)
+
+	uuid := godoc2api.String("uuid").Pattern(`^[a-f0-9-]{36}$`).MaxLength(36)
+	godoc2api.Object("Item").
+		Description("An item of the catalog").
+		Prop("id", uuid).
+		Prop("name", godoc2api.Ref("string").Description("Name of the item")).
+		Optional("stars", godoc2api.Integer("").Minimum(0).Maximum(5).Format("int8")).
+		Optional("picture", godoc2api.File("").FileTypes("image/png")).
+		Define()
+	godoc2api.Object("Catalog").
+		Prop("items", godoc2api.Array("", godoc2api.Ref("Item")).MaxItems(100)).
+		Prop("tags", godoc2api.Array("", godoc2api.String("").Enum("new", "sale")).UniqueItems(true)).
+		Prop("updated", godoc2api.Date("", "datetime").Format("rfc2616")).
+		Define()
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	err := doc.AddRoute(GetCatalogHandler)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+
+	err = doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+// Get the catalog
+// @resource GET /catalog
+// @response {Catalog}
+func GetCatalogHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
@@ -0,0 +1,68 @@
+package godoc2api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/florenthobein/godoc2api"
+)
+
+func TestWithDefaults(t *testing.T) {
+	output_dir := "test23"
+	defer finalize(output_dir, t)
+
+	defineOAuth()
+	defineAPIKey()
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+		SecuredBy:   []string{"oauth"},
+	}
+	doc.SecurePrefix("/admin", "auth")
+	doc.ApplyTraits("/admin/users", "pagination")
+
+	for _, handler := range []interface{}{ListUsersHandler, StatusHandler, ListShelvesHandler, ReadShelfHandler} {
+		err := doc.AddRoute(handler)
+		if err != nil {
+			t.Errorf(err.Error())
+			return
+		}
+	}
+
+	err := doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+// List the users
+// @resource GET /admin/users
+func ListUsersHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+// Status of the API
+// @resource GET /admin/status
+// @public
+func StatusHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+// List the shelves
+// @resource GET /shelves
+// @pagination
+func ListShelvesHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+// Read a shelf
+// @resource GET /shelves/{id}
+// @route {string} [id] Identifier of the shelf
+// @apikey
+func ReadShelfHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
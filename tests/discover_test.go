@@ -0,0 +1,137 @@
+package godoc2api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/florenthobein/godoc2api"
+	"github.com/florenthobein/godoc2api/tests/auth"
+	"github.com/florenthobein/godoc2api/tests/billing"
+)
+
+func TestWithDiscoveredTypes(t *testing.T) {
+	output_dir := "test11"
+	defer finalize(output_dir, t)
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	// None of the types are defined
+	err := doc.AddRoute(GetShelvesHandler)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+	err = doc.AddRoute(CreateShelfHandler)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+
+	err = doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+// A shelf of the library
+type Shelf struct {
+	Id    ShelfId   `json:"id"`
+	Books Volumes   `json:"books"`
+	Genre Genre     `json:"genre,omitempty"`
+	Since time.Time `json:"since"`
+	Tags  []string  `json:"tags" validate:"max=5"`
+}
+
+type ShelfId int64
+
+// A list of volumes
+type Volumes []Volume
+
+type Volume struct {
+	Title  string            `json:"title"`
+	Pages  uint16            `json:"pages"`
+	Shelf  *Shelf            `json:"shelf,omitempty"`
+	Extras map[string]Extra  `json:"extras"`
+	Any    interface{}       `json:"any"`
+	Notes  map[string]string `json:"notes"`
+}
+
+type Extra struct {
+	Label string `json:"label"`
+}
+
+// Genre of the books
+type Genre string
+
+const (
+	GenreNovel  Genre = "novel"  // Fictions
+	GenreEssay  Genre = "essay"  // Non fictions
+	GenrePoetry Genre = "poetry" // Poems
+)
+
+// List the shelves
+// @resource GET /shelves
+// @response {[]Shelf}
+func GetShelvesHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+// Create a shelf
+// @resource POST /shelves
+// @body {godoc2api_test.Shelf}
+// @response {Shelf}
+func CreateShelfHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+func TestWithCollidingTypes(t *testing.T) {
+	output_dir := "test12"
+	defer finalize(output_dir, t)
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	// Both packages export an `Error`
+	err := doc.AddRoute(PayHandler)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+	err = doc.AddRoute(LoginHandler)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+
+	err = doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+// Pay an invoice
+// @resource POST /pay
+// @response {billing.Error}
+func PayHandler(rw http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(rw).Encode(billing.Error{})
+}
+
+// Log in
+// @resource POST /login
+// @response {auth.Error}
+func LoginHandler(rw http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(rw).Encode(auth.Error{})
+}
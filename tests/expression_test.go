@@ -0,0 +1,61 @@
+package godoc2api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/florenthobein/godoc2api"
+)
+
+func TestWithTypeExpressions(t *testing.T) {
+	output_dir := "test13"
+	defer finalize(output_dir, t)
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	err := doc.AddRoute(GetPetsHandler)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+	err = doc.AddRoute(AdoptPetHandler)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+
+	err = doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+type Dog struct {
+	Barks bool `json:"barks"`
+}
+
+type Cat struct {
+	Lives int `json:"lives"`
+}
+
+// List the pets, grouped by owner
+// @resource GET /pets
+// @query {(string | integer)[]} [ids] Identifiers of the pets
+// @response {map[string][](Dog | Cat)}
+func GetPetsHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+// Adopt a pet
+// @resource POST /pets
+// @body {Dog | Cat}
+// @response {(Dog | Cat | nil)[]}
+func AdoptPetHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
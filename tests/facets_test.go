@@ -0,0 +1,60 @@
+package godoc2api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/florenthobein/godoc2api"
+)
+
+func TestWithFacets(t *testing.T) {
+	output_dir := "test19"
+	defer finalize(output_dir, t)
+
+	godoc2api.DefineTypeRAML("percent", "number", map[string]interface{}{"minimum": 0, "maximum": 100, "format": "float", "multipleOf": 1.0})
+	godoc2api.DefineTypeRAML("labels", "array", map[string]interface{}{"items": "string", "minItems": 1, "maxItems": 10.0, "uniqueItems": true})
+	godoc2api.DefineTypeRAML("avatar", "file", map[string]interface{}{"fileTypes": []string{"image/png", "image/jpeg"}, "maxLength": 307200})
+	godoc2api.DefineTypeRAML("birthday", "date-only", map[string]interface{}{"description": "Day of birth", "example": "1990-01-31"})
+	godoc2api.DefineTypeRAML("modified", "datetime", map[string]interface{}{"format": "rfc2616"})
+	godoc2api.DefineTypeRAML("settings", "object", map[string]interface{}{"properties": map[string]interface{}{"theme": "string"}, "maxProperties": 5, "additionalProperties": true})
+	// Wrong values are ignored
+	godoc2api.DefineTypeRAML("nickname", "string", map[string]interface{}{"minLength": "3", "maxLength": 2.5, "pattern": "^[a-z]+$", "minimum": 0})
+	godoc2api.DefineType("Profile", Profile{})
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	err := doc.AddRoute(GetProfileHandler)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+
+	err = doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+type Profile struct {
+	Completion float32           `json:"completion" ramlType:"percent"`
+	Labels     []string          `json:"labels" ramlType:"labels"`
+	Avatar     []byte            `json:"avatar" ramlType:"avatar"`
+	Birthday   string            `json:"birthday" ramlType:"birthday"`
+	Modified   string            `json:"modified" ramlType:"modified"`
+	Settings   map[string]string `json:"settings" ramlType:"settings"`
+	Nickname   string            `json:"nickname" ramlType:"nickname"`
+}
+
+// Get a profile
+// @resource GET /profiles/{id}
+// @route {string} [id] Identifier of the profile
+// @response {Profile}
+func GetProfileHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
@@ -0,0 +1,107 @@
+package godoc2api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/florenthobein/godoc2api"
+)
+
+func TestWithGenerics(t *testing.T) {
+	output_dir := "test18"
+	defer finalize(output_dir, t)
+
+	godoc2api.DefineType("Artist", Artist{})
+	godoc2api.DefineType("AlbumEntry", Pair[string, Album]{})
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	for _, handler := range []interface{}{ListAlbumsHandler, SearchAlbumsHandler, GetArtistHandler} {
+		err := doc.AddRoute(handler)
+		if err != nil {
+			t.Errorf(err.Error())
+			return
+		}
+	}
+
+	err := doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+// A page of a list
+type Page[T any] struct {
+	Items []T    `json:"items"`
+	Total int    `json:"total"`
+	Next  string `json:"next,omitempty"`
+}
+
+// The result of an operation
+type Result[T any] struct {
+	Data  T       `json:"data"`
+	Error *string `json:"error"`
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+type Album struct {
+	Title string `json:"title"`
+}
+
+type Artist struct {
+	Name    string                   `json:"name"`
+	Albums  Page[Album]              `json:"albums"`
+	Entries []Pair[string, Album]    `json:"entries"`
+	Ratings Page[map[string]float32] `json:"ratings"`
+}
+
+// List the albums
+// @resource GET /albums
+// @response {Page[Album]}
+func ListAlbumsHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+// Search the albums
+// @resource GET /albums/search
+// @response {Result[Page[Album]]}
+func SearchAlbumsHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+// Get an artist
+// @resource GET /artists/{id}
+// @route {string} [id] Identifier of the artist
+// @response {Artist}
+func GetArtistHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+func TestWithUnknownTypeArgument(t *testing.T) {
+	doc := godoc2api.Documentation{
+		Title:   "Test API",
+		Version: "v1",
+		URL:     "http://mywebsite/{version}",
+	}
+	// The response is ignored with a warning, the route still being documented
+	if err := doc.AddRoute(ListUnknownPagesHandler); err != nil {
+		t.Errorf(err.Error())
+	}
+}
+
+// List pages of an unknown type
+// @resource GET /unknown
+// @response {Page[Unknown[Album]]}
+func ListUnknownPagesHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
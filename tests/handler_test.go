@@ -0,0 +1,67 @@
+package godoc2api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/florenthobein/godoc2api"
+)
+
+func TestWithHandlerTypes(t *testing.T) {
+	output_dir := "test5"
+	defer finalize(output_dir, t)
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	s := &Service{}
+	handlers := []interface{}{
+		s.ListItems,           // method value
+		ItemHandler{},         // http.Handler implementation
+		NewItemsDeleter(true), // closure returned by a factory
+	}
+	for _, h := range handlers {
+		err := doc.AddRoute(h)
+		if err != nil {
+			t.Errorf(err.Error())
+			return
+		}
+	}
+
+	err := doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+type Service struct{}
+
+// List the items of the service
+// @resource GET /items
+// @response {[]MyStruct}
+func (s *Service) ListItems(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+// Get an item
+// @resource GET /items/{id}
+// @route {string} id - The id of the item
+// @response {MyStruct}
+type ItemHandler struct{}
+
+func (h ItemHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+// Delete all the items
+// @resource DELETE /items
+func NewItemsDeleter(soft bool) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(204)
+	}
+}
@@ -53,11 +53,13 @@ func compare(folder string, t *testing.T) {
 	// Get fixture
 	fixture, err := ioutil.ReadFile("fixtures/" + folder + "/test_api_v1.raml")
 	if err != nil {
+		t.Errorf("missing fixture for %s: %v", folder, err)
 		return
 	}
 	// Get the result
 	result, err := ioutil.ReadFile(folder + "/test_api_v1.raml")
 	if err != nil {
+		t.Errorf("missing result for %s: %v", folder, err)
 		return
 	}
 	if string(result) != string(fixture) {
@@ -0,0 +1,103 @@
+package godoc2api_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/florenthobein/godoc2api"
+	"github.com/florenthobein/godoc2api/raml"
+)
+
+func TestWithMarshalers(t *testing.T) {
+	output_dir := "test15"
+	defer finalize(output_dir, t)
+
+	godoc2api.DefineType("Invoice", Invoice{})
+	godoc2api.DefineWireType(Reference{}, raml.Type{Type: "string", Description: "Reference of the invoice"})
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	err := doc.AddRoute(GetInvoiceHandler)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+
+	err = doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+type Invoice struct {
+	Reference Reference         `json:"reference"`
+	Total     Money             `json:"total"`
+	Lines     map[string]Money  `json:"lines"`
+	Location  Location          `json:"location"`
+	Color     Color             `json:"color"`
+	Status    InvoiceStatus     `json:"status"`
+	Extra     map[string]string `json:"extra"`
+}
+
+// An amount of money, encoded like "12.30 EUR"
+type Money struct {
+	Cents    int64
+	Currency string
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`"%d.%02d %s"`, m.Cents/100, m.Cents%100, m.Currency)), nil
+}
+
+// Coordinates encoded as [lat, lng]
+type Location struct {
+	Lat float64
+	Lng float64
+}
+
+func (l Location) RAMLType() raml.Type {
+	return raml.Type{Type: "number[]", Description: "Latitude and longitude"}
+}
+
+type Reference struct {
+	Year   int
+	Number int
+}
+
+func (r *Reference) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d-%d", r.Year, r.Number)), nil
+}
+
+// A color, encoded by its name
+type Color int
+
+func (c Color) MarshalText() ([]byte, error) {
+	return []byte("red"), nil
+}
+
+type InvoiceStatus string
+
+const (
+	InvoiceDraft InvoiceStatus = "draft"
+	InvoicePaid  InvoiceStatus = "paid"
+)
+
+// Encoded as is
+func (s InvoiceStatus) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// Get an invoice
+// @resource GET /invoices/{id}
+// @route {string} [id] Identifier of the invoice
+// @response {Invoice}
+func GetInvoiceHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
@@ -0,0 +1,57 @@
+package godoc2api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/florenthobein/godoc2api"
+)
+
+func TestWithMediaTypes(t *testing.T) {
+	output_dir := "test27"
+	defer finalize(output_dir, t)
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	for _, handler := range []interface{}{UploadHandler, ExportHandler} {
+		err := doc.AddRoute(handler)
+		if err != nil {
+			t.Errorf(err.Error())
+			return
+		}
+	}
+
+	err := doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+type UploadForm struct {
+	Name string `json:"name"`
+	File []byte `json:"file"`
+}
+
+// Upload a file
+// @resource POST /uploads
+// @body multipart/form-data {UploadForm} The file and its name
+// @response 201 {UploadForm} The uploaded file
+// @response 413 The file is too large
+// @response 409 Name/path already taken
+func UploadHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(201)
+}
+
+// Export the statistics
+// @resource GET /exports
+// @response {MyStruct} The statistics
+// @response 200 text/csv {string} The statistics
+func ExportHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
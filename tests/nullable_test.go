@@ -0,0 +1,59 @@
+package godoc2api_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/florenthobein/godoc2api"
+)
+
+func TestWithNullables(t *testing.T) {
+	output_dir := "test17"
+	defer finalize(output_dir, t)
+
+	godoc2api.DefineType("Account", Account{})
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	err := doc.AddRoute(GetAccountHandler)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+
+	err = doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+type Account struct {
+	Id       int64             `json:"id,string"`
+	Balance  *float64          `json:"balance,string"`
+	Active   bool              `json:"active,string,omitempty"`
+	Nickname *string           `json:"nickname,omitempty"`
+	Manager  *Account          `json:"manager"`
+	Roles    []string          `json:"roles" validate:"required"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels,omitzero"`
+	Score    *int              `json:"score" validate:"max=100"`
+	Aliases  []string          `json:"aliases" validate:"max=3"`
+	Credit   *int64            `json:"credit"`
+	Quota    *uint             `json:"quota"`
+	Closed   *time.Time        `json:"closed"`
+}
+
+// Get an account
+// @resource GET /accounts/{id}
+// @route {string} [id] Identifier of the account
+// @response {Account}
+func GetAccountHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
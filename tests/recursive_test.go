@@ -0,0 +1,136 @@
+package godoc2api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/florenthobein/godoc2api"
+)
+
+func TestWithRecursiveTypes(t *testing.T) {
+	output_dir := "test9"
+	defer finalize(output_dir, t)
+
+	godoc2api.DefineType("Category", Category{})
+	godoc2api.DefineType("Node", Node{})
+	godoc2api.DefineType("Author", Author{})
+	godoc2api.DefineType("Novel", Novel{})
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	err := doc.AddRoute(GetCategoriesHandler)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+
+	err = doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+// Self-referential through a slice and a map
+type Category struct {
+	Name     string              `json:"name"`
+	Children []Category          `json:"children"`
+	Related  map[string]Category `json:"related"`
+	Root     *Node               `json:"root"`
+}
+
+// Self-referential through a pointer
+type Node struct {
+	Parent   *Node    `json:"parent"`
+	Children []*Node  `json:"children"`
+	Authors  []Author `json:"authors"`
+}
+
+// Mutually recursive
+type Author struct {
+	Novels []Novel `json:"novels"`
+}
+
+type Novel struct {
+	Author  *Author             `json:"author"`
+	Sequels map[string][]*Novel `json:"sequels"`
+}
+
+// Get the categories
+// @resource GET /categories
+// @response {[]Category}
+func GetCategoriesHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+func TestWithDeepTypes(t *testing.T) {
+	output_dir := "test10"
+	defer finalize(output_dir, t)
+
+	godoc2api.DefineType("Level1", &Level1{}) // defined through a pointer
+	godoc2api.DefineType("Level2", Level2{})
+	godoc2api.DefineType("Level3", Level3{})
+	godoc2api.DefineType("Level4", Level4{})
+	godoc2api.DefineType("Level5", Level5{})
+	godoc2api.DefineType("Cyclic", Cyclic{})
+	godoc2api.DefineType("CyclicBase", CyclicBase{})
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	err := doc.AddRoute(GetLevelsHandler)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+
+	err = doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+type Level1 struct {
+	Next  *Level2          `json:"next"`
+	Cycle map[int][]Level5 `json:"cycle"`
+}
+type Level2 struct {
+	Next []Level3 `json:"next"`
+}
+type Level3 struct {
+	Next map[string]*Level4 `json:"next"`
+}
+type Level4 struct {
+	Next [][]Level5 `json:"next"`
+}
+type Level5 struct {
+	Back   *Level1 `json:"back"`
+	Cyclic Cyclic  `json:"cyclic"`
+}
+
+// Embedding cycle, that can't be rendered as an inheritance
+type Cyclic struct {
+	CyclicBase
+	Name string `json:"name"`
+}
+type CyclicBase struct {
+	*Cyclic
+	Id string `json:"id"`
+}
+
+// Get the levels
+// @resource GET /levels
+// @response {Level1}
+func GetLevelsHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
@@ -0,0 +1,74 @@
+package godoc2api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/florenthobein/godoc2api"
+)
+
+func TestWithRequestStruct(t *testing.T) {
+	output_dir := "test6"
+	defer finalize(output_dir, t)
+
+	godoc2api.DefineType("ListItemsRequest", ListItemsRequest{})
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	// The query parameters are described by a struct in the comment
+	err := doc.AddRoute(ListItemsHandler)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+
+	// The whole request is described by a struct in the route definition
+	err = doc.AddRoute(RequestRouteDefinition{
+		Resource: "PUT /items/{id}",
+		Handler:  MyHanderWithoutComment,
+		Request:  UpdateItemRequest{},
+		Response: "{MyStruct}",
+	})
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+
+	err = doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+type RequestRouteDefinition struct {
+	Resource string           `raml:"resource"`
+	Handler  http.HandlerFunc `raml:"handler"`
+	Request  interface{}      `raml:"request"`
+	Response string           `raml:"response"`
+}
+
+type ListItemsRequest struct {
+	Limit int    `query:"limit" default:"10" description:"Maximum number of items"`
+	Sort  string `query:"sort" enum:"asc,desc"`
+}
+
+type UpdateItemRequest struct {
+	Id     string `uri:"id" description:"The id of the item"`
+	Token  string `header:"X-Token"`
+	Name   string `json:"name"`
+	Amount int    `json:"amount,omitempty"`
+}
+
+// List the items
+// @resource GET /items
+// @query {ListItemsRequest}
+// @response {[]MyStruct}
+func ListItemsHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
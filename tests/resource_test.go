@@ -0,0 +1,63 @@
+package godoc2api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/florenthobein/godoc2api"
+)
+
+func TestWithResourceDescriptions(t *testing.T) {
+	output_dir := "test25"
+	defer finalize(output_dir, t)
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+	doc.DescribeResource("/library", "Library", "The catalogue of the library")
+	doc.DescribeResource("/library/shelves/{id}", "Shelf", "A shelf of the library")
+	doc.DescribeResource("/library/floors/{floor}", "Floor", "A floor of the library")
+
+	for _, handler := range []interface{}{ListLibraryShelvesHandler, ListShelfBooksHandler, ReadRoomHandler} {
+		err := doc.AddRoute(handler)
+		if err != nil {
+			t.Errorf(err.Error())
+			return
+		}
+	}
+
+	err := doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+// List the shelves of the library
+// @resource GET /library/shelves
+// @resourceDescription Shelves
+//
+// The shelves of the library, \
+// by floor
+func ListLibraryShelvesHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+// List the books of a shelf
+// @resource GET /library/shelves/{id}/books
+// @route {string} [id] Identifier of the shelf
+// @resourceDescription The books of a shelf
+func ListShelfBooksHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+// Read a room of a floor, the described floor only having its own parameter
+// @resource GET /library/floors/{floor}/rooms/{room}
+// @route {int} [floor] Number of the floor
+// @route {string} [room] Name of the room
+func ReadRoomHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
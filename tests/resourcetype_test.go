@@ -0,0 +1,94 @@
+package godoc2api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/florenthobein/godoc2api"
+)
+
+func TestWithResourceTypes(t *testing.T) {
+	output_dir := "test24"
+	defer finalize(output_dir, t)
+
+	godoc2api.DefineResourceType("collection", godoc2api.ResourceType{
+		Description: "Collection of <<resourcePathName>>",
+		Methods: map[string]godoc2api.ResourceTypeMethod{
+			"GET": {
+				Description: "List the <<resourcePathName>>",
+				QueryParameters: map[string]godoc2api.Parameter{
+					"page": {Type: "int", Description: "Index of the page"},
+				},
+				Response: "<<item>>[]",
+			},
+			"POST": {Description: "Add an item to the <<resourcePathName>>", Body: "<<item>>", Response: "<<item>>"},
+		},
+	})
+	godoc2api.DefineResourceType("item", godoc2api.ResourceType{
+		Usage: "Apply to the items of a collection",
+		Methods: map[string]godoc2api.ResourceTypeMethod{
+			"GET":    {Description: "Read the <<label>>", Response: "<<item>>"},
+			"DELETE": {Description: "Delete the <<label>>", Response: "ApiError | nil"},
+		},
+	})
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	for _, handler := range []interface{}{ListRecordsHandler, AddRecordHandler, ReadRecordHandler, ListDraftsHandler, AddDraftHandler} {
+		err := doc.AddRoute(handler)
+		if err != nil {
+			t.Errorf(err.Error())
+			return
+		}
+	}
+
+	err := doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+// @resource GET /records
+// @resourceType collection item={Album}
+func ListRecordsHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+// Add an album, its artist included
+// @resource POST /records
+// @resourceType collection item={Album}
+// @body {Album}
+// @response {Album}
+func AddRecordHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+// @resource GET /records/{id}
+// @route {string} [id] Identifier of the album
+// @resourceType item item={Album} label=album
+// @response {Album}
+func ReadRecordHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+// @resource GET /drafts
+// @resourceType collection item={Album | nil}
+// @response {(Album | nil)[]}
+func ListDraftsHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+// Add a draft, the response differing from the one of the resource type
+// @resource POST /drafts
+// @resourceType collection item={Album | nil}
+// @body {Album | nil}
+// @response {string} Identifier of the draft
+func AddDraftHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
@@ -0,0 +1,102 @@
+package godoc2api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/florenthobein/godoc2api"
+)
+
+func TestWithServeMux(t *testing.T) {
+	output_dir := "test4"
+	defer finalize(output_dir, t)
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	// The router defines the method and the resource
+	mux := doc.ServeMux(http.NewServeMux())
+	mux.HandleFunc("GET /myroute/{id}", MyHanderWithFewComments)
+	if errs := mux.Errors(); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+
+	// A route that can't be documented is reported
+	mux.HandleFunc("/nomethod", MyHanderWithoutComment)
+	if errs := mux.Errors(); len(errs) != 1 {
+		t.Errorf("expected an error for the route without method, got %v", errs)
+	}
+
+	// Patterns of the parameters are ignored
+	err := doc.AddRouterRoute("DELETE", "/myroute/{id:[0-9]{1,3}}", MyHanderWithFewComments)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+
+	err = doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+func TestWithMuxRoute(t *testing.T) {
+	output_dir := "test28"
+	defer finalize(output_dir, t)
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	err := doc.AddMuxRoute(muxRoute{"/myroute/{id}", []string{"GET", "HEAD"}, http.HandlerFunc(MyHanderWithFewComments)})
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+
+	// Routes without handler are ignored
+	err = doc.AddMuxRoute(muxRoute{"/myroute", nil, nil})
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+
+	err = doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+func TestWithChiWalkFunc(t *testing.T) {
+	doc := godoc2api.Documentation{}
+
+	// The error of a route that can't be documented stops the walk
+	err := doc.ChiWalkFunc("", "/nomethod", http.HandlerFunc(MyHanderWithoutComment))
+	if err == nil {
+		t.Errorf("expected an error for the route without method")
+	}
+	err = doc.ChiWalkFunc("GET", "/myroute/{id}", http.HandlerFunc(MyHanderWithFewComments))
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+}
+
+// Minimal implementation of a gorilla/mux route
+type muxRoute struct {
+	path    string
+	methods []string
+	handler http.Handler
+}
+
+func (r muxRoute) GetPathTemplate() (string, error) { return r.path, nil }
+func (r muxRoute) GetMethods() ([]string, error)    { return r.methods, nil }
+func (r muxRoute) GetHandler() http.Handler         { return r.handler }
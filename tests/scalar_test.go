@@ -0,0 +1,74 @@
+package godoc2api_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/florenthobein/godoc2api"
+	"github.com/florenthobein/godoc2api/raml"
+)
+
+func TestWithScalars(t *testing.T) {
+	output_dir := "test16"
+	defer finalize(output_dir, t)
+
+	godoc2api.DefineType("Measure", Measure{})
+	godoc2api.DefineScalar(Decimal{}, raml.Type{
+		Type:        "string",
+		Description: "Decimal number",
+		StringType:  raml.StringType{Pattern: &decimal_pattern},
+	})
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	err := doc.AddRoute(GetMeasureHandler)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+
+	err = doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+var decimal_pattern = `^-?[0-9]+(\.[0-9]+)?$`
+
+type Measure struct {
+	Id       int64           `json:"id"`
+	Value    float32         `json:"value"`
+	Ratio    Decimal         `json:"ratio"`
+	Duration time.Duration   `json:"duration"`
+	Raw      []byte          `json:"raw"`
+	Payload  json.RawMessage `json:"payload"`
+	Source   net.IP          `json:"source"`
+	Link     url.URL         `json:"link"`
+	Count    big.Int         `json:"count"`
+	Label    sql.NullString  `json:"label"`
+	Taken    *time.Time      `json:"taken"`
+}
+
+type Decimal struct {
+	digits string
+}
+
+// Get a measure
+// @resource GET /measures/{id}
+// @route {uint32} [id] Identifier of the measure
+// @response {Measure}
+func GetMeasureHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
@@ -0,0 +1,205 @@
+package godoc2api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/florenthobein/godoc2api"
+)
+
+// Configure the OAuth security scheme used by a test
+func defineOAuth() {
+	godoc2api.DefineSecurity("oauth", godoc2api.Security{
+		Type:        godoc2api.SECURITY_OAUTH_2,
+		Description: "Authenticate a user with OAuth 2.0",
+		OAuth2: &godoc2api.OAuth2Settings{
+			AuthorizationURI:    "https://mywebsite/oauth/authorize",
+			AccessTokenURI:      "https://mywebsite/oauth/token",
+			AuthorizationGrants: []string{"authorization_code", "client_credentials"},
+			Scopes:              []string{"books:read", "books:write"},
+		},
+	})
+}
+
+func TestWithScopes(t *testing.T) {
+	output_dir := "test21"
+	defer finalize(output_dir, t)
+
+	defineOAuth()
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	for _, handler := range []interface{}{ReadBooksHandler, WriteBookHandler} {
+		err := doc.AddRoute(handler)
+		if err != nil {
+			t.Errorf(err.Error())
+			return
+		}
+	}
+
+	err := doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+// List the books
+// @resource GET /books
+// @oauth
+// @auth
+func ReadBooksHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+// Update a book
+// @resource PUT /books/{id}
+// @route {string} [id] Identifier of the book
+// @oauth books:read, books:write
+func WriteBookHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+func TestWithSecurityTag(t *testing.T) {
+	output_dir := "test29"
+	defer finalize(output_dir, t)
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	for _, handler := range []interface{}{ReadProfileHandler, ReadNewsHandler} {
+		err := doc.AddRoute(handler)
+		if err != nil {
+			t.Errorf(err.Error())
+			return
+		}
+	}
+
+	err := doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+// Read the profile of the user, the tag of the security without scopes
+// @resource GET /profile
+// @auth
+func ReadProfileHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+// Read the news
+// @resource GET /news
+func ReadNewsHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+// Configure the API key security scheme used by a test, and its types
+func defineAPIKey() {
+	godoc2api.DefineType("ApiKeyQuery", ApiKeyQuery{})
+	godoc2api.DefineType("ApiError", ApiError{})
+	godoc2api.DefineSecurity("apikey", godoc2api.Security{
+		Type:        godoc2api.SECURITY_PASS_THROUGH,
+		Description: "Authenticate an application with its API key",
+		QueryString: godoc2api.Parameter{
+			Type:        "ApiKeyQuery",
+			Description: "The API key of the application",
+		},
+		Responses: map[int]godoc2api.Response{
+			401: {Type: "ApiError", Description: "The API key is missing or invalid"},
+			403: {Type: "ApiError", Description: "The API key is revoked"},
+		},
+	})
+}
+
+func TestWithSecurityResponses(t *testing.T) {
+	output_dir := "test22"
+	defer finalize(output_dir, t)
+
+	defineAPIKey()
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	for _, handler := range []interface{}{ListStatsHandler, ResetStatsHandler} {
+		err := doc.AddRoute(handler)
+		if err != nil {
+			t.Errorf(err.Error())
+			return
+		}
+	}
+
+	err := doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+type ApiKeyQuery struct {
+	Key string `json:"api_key"`
+}
+
+type ApiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func TestWithExclusiveQueryString(t *testing.T) {
+	godoc2api.DefineSecurity("badkey", godoc2api.Security{
+		Type:            godoc2api.SECURITY_PASS_THROUGH,
+		QueryString:     godoc2api.Parameter{Type: "string"},
+		QueryParameters: map[string]godoc2api.Parameter{"api_key": {Type: "string"}},
+	})
+
+	doc := godoc2api.Documentation{
+		Title:   "Test API",
+		Version: "v1",
+		URL:     "http://mywebsite/{version}",
+	}
+	err := doc.AddRoute(ListBadStatsHandler)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+	if err := doc.Print(); err == nil {
+		t.Errorf("expected an error for the query string and the query parameters both set")
+	}
+}
+
+// List the statistics
+// @resource GET /stats
+// @apikey
+// @response {MyStruct} The statistics
+func ListStatsHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+// Reset the statistics
+// @resource DELETE /stats
+// @apikey
+// @auth
+func ResetStatsHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+// List the statistics, with an API key scheme defined twice
+// @resource GET /stats
+// @badkey
+func ListBadStatsHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
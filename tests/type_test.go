@@ -0,0 +1,142 @@
+package godoc2api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/florenthobein/godoc2api"
+)
+
+func TestWithTypes(t *testing.T) {
+	output_dir := "test7"
+	defer finalize(output_dir, t)
+
+	godoc2api.DefineType("Product", Product{})
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	err := doc.AddRoute(GetProductHandler)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+
+	err = doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+// A product of the catalogue
+type Product struct {
+	// Name of the product,
+	// as displayed in the catalogue
+	Name  string  `json:"name"`
+	Price float64 `json:"price"` // Price in euros, VAT included
+	Stock int     `json:"stock" description:"Number of products in stock"`
+
+	Code     string   `json:"code,omitempty" validate:"required,len=8"`
+	Quantity int      `json:"quantity" validate:"omitempty,min=1,max=100"`
+	Sort     string   `json:"sort" binding:"oneof=asc desc"`
+	Contact  string   `json:"contact" validate:"email"`
+	Tags     []string `json:"tags" validate:"max=5,dive,min=2"`
+
+	Status   ProductStatus `json:"status"`
+	Priority Priority      `json:"priority"`
+}
+
+// Status of a product
+type ProductStatus string
+
+const (
+	ProductAvailable    ProductStatus = "available" // Can be ordered
+	ProductSoldOut      ProductStatus = "sold_out"  // Out of stock
+	ProductDiscontinued ProductStatus = "discontinued"
+)
+
+type Priority int
+
+const (
+	PriorityLow Priority = iota + 1
+	PriorityMedium
+	PriorityHigh
+)
+
+// Get a product
+// @resource GET /products/{id}
+// @route {string} id - The id of the product
+// @response {Product}
+func GetProductHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
+
+func TestWithEmbeddedTypes(t *testing.T) {
+	output_dir := "test8"
+	defer finalize(output_dir, t)
+
+	godoc2api.DefineType("Model", Model{})
+	godoc2api.DefineType("Order", Order{})
+	godoc2api.DefineType("Address", Address{})
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	err := doc.AddRoute(GetOrderHandler)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+
+	err = doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+// Defined, so inherited
+type Model struct {
+	Id string `json:"id"`
+}
+
+// Not defined, so its fields are promoted
+type Timestamps struct {
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type Address struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+}
+
+type Order struct {
+	Model
+	*Timestamps
+	Address   `json:"address"` // Delivery address, not promoted as it is named
+	Billing   Address          `json:",inline"`
+	UpdatedAt int              `json:"updated_at"` // Timestamp of the last update, shadows the one of Timestamps
+	internal
+}
+
+type internal struct {
+	Secret string `json:"secret"`
+}
+
+// Get an order
+// @resource GET /orders/{id}
+// @route {string} id - The id of the order
+// @response {Order}
+func GetOrderHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
@@ -0,0 +1,57 @@
+package godoc2api_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/florenthobein/godoc2api"
+)
+
+func TestWithUnion(t *testing.T) {
+	output_dir := "test14"
+	defer finalize(output_dir, t)
+
+	godoc2api.DefineUnion("Event", "type", map[string]interface{}{
+		"order.created": OrderCreated{},
+		"order.shipped": &OrderShipped{},
+	})
+
+	doc := godoc2api.Documentation{
+		Title:       "Test API",
+		Description: "API used for tests",
+		Version:     "v1",
+		URL:         "http://mywebsite/{version}",
+	}
+
+	err := doc.AddRoute(GetEventsHandler)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+
+	err = doc.Save(output_dir)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+}
+
+type OrderCreated struct {
+	Type  string  `json:"type"`
+	Order string  `json:"order"`
+	Total float64 `json:"total"`
+}
+
+type OrderShipped struct {
+	Type    string    `json:"type"`
+	Order   string    `json:"order"`
+	Shipped time.Time `json:"shipped"`
+}
+
+// List the last events
+// @resource GET /events
+// @response {Event[]}
+func GetEventsHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(200)
+}
@@ -0,0 +1,21 @@
+package godoc2api
+
+import "testing"
+
+func TestUnionOfVariantSeveralUnions(t *testing.T) {
+	defer func(unions map[string]unionDefinition) { index_unions = unions }(index_unions)
+
+	index_unions = map[string]unionDefinition{}
+	for _, name := range []string{"Event", "Audit", "Message", "Change"} {
+		index_unions[name] = unionDefinition{
+			discriminator: "type",
+			variants:      map[string]string{"order.created": "OrderCreated", "created": "OrderCreated"},
+		}
+	}
+	for i := 0; i < 10; i++ {
+		union, value, ok := unionOfVariant("OrderCreated")
+		if !ok || union != "Audit" || value != "created" {
+			t.Errorf("expected the union Audit and the value created, got %s and %s", union, value)
+		}
+	}
+}
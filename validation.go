@@ -0,0 +1,126 @@
+// The validation is responsible for translating the constraints
+// of the struct tags used by validators (go-playground/validator, gin's binding)
+// into RAML facets
+
+package godoc2api
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/florenthobein/godoc2api/raml"
+)
+
+// Tags read to find the constraints of a field
+var validation_tags_names = []string{"validate", "binding"}
+
+// Patterns of the formats known by the validators
+var validation_patterns = map[string]string{
+	"email": `^[^@\s]+@[^@\s]+\.[^@\s]+$`,
+	"uuid":  `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`,
+	"uuid4": `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-4[0-9a-fA-F]{3}-[89abAB][0-9a-fA-F]{3}-[0-9a-fA-F]{12}$`,
+	"url":   `^[a-zA-Z][a-zA-Z0-9+.-]*://.+$`,
+	"uri":   `^[a-zA-Z][a-zA-Z0-9+.-]*:.+$`,
+}
+
+// Apply the constraints of a field to its RAML type, according to the kind of the field.
+// Returns if the field is explicitly required.
+// The rule `omitempty` only skips the validation of the empty values,
+// the optional fields being the ones omitted from the JSON.
+func applyValidation(tag reflect.StructTag, kind reflect.Kind, t *raml.Type) (required bool) {
+	value := ""
+	for _, name := range validation_tags_names {
+		if value = tag.Get(name); value != "" {
+			break
+		}
+	}
+	if value == "" || value == "-" {
+		return
+	}
+
+	// Set a minimum or a maximum according to the kind of the field
+	var bound = func(s string, is_min bool) {
+		switch kind {
+		case reflect.String:
+			if v, err := strconv.Atoi(s); err == nil {
+				if is_min {
+					t.StringType.MinLength = &v
+				} else {
+					t.StringType.MaxLength = &v
+				}
+			}
+		case reflect.Slice, reflect.Array:
+			if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+				if is_min {
+					t.ArrayType.MinItems = v
+				} else {
+					t.ArrayType.MaxItems = v
+				}
+			}
+		case reflect.Map:
+			if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+				if is_min {
+					t.ObjectType.MinProperties = v
+				} else {
+					t.ObjectType.MaxProperties = v
+				}
+			}
+		default:
+			if v, err := strconv.ParseFloat(s, 64); err == nil {
+				if is_min {
+					t.NumberType.Minimum = &v
+				} else {
+					t.NumberType.Maximum = &v
+				}
+			}
+		}
+	}
+
+	for _, rule := range strings.Split(value, ",") {
+		kv := strings.SplitN(rule, "=", 2)
+		key, param := kv[0], ""
+		if len(kv) > 1 {
+			param = kv[1]
+		}
+		switch key {
+		case "dive":
+			// Following rules apply to the items
+			return
+		case "required":
+			required = true
+		case "min", "gte":
+			bound(param, true)
+		case "max", "lte":
+			bound(param, false)
+		case "len":
+			bound(param, true)
+			bound(param, false)
+		case "oneof":
+			values := regexp.MustCompile(`'[^']*'|\S+`).FindAllString(param, -1)
+			for i, v := range values {
+				values[i] = strings.Trim(v, "'")
+			}
+			enum := []raml.AnyType{}
+			for _, v := range parseValues(values, scalarRAMLType(t.Type)) {
+				enum = append(enum, v)
+			}
+			t.Enum = enum
+		default:
+			if pattern, ok := validation_patterns[key]; ok {
+				t.StringType.Pattern = &pattern
+			}
+		}
+	}
+	return
+}
+
+// Name of the scalar RAML type, used to parse values
+func scalarRAMLType(t interface{}) string {
+	name, _ := t.(Type)
+	if td, ok := isDefinedTypeRAML(string(name)); ok {
+		return td.nameRAMLType
+	}
+	return string(name)
+}